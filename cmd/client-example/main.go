@@ -0,0 +1,57 @@
+// Приклад використання пакету client: малює зелену рамку з двома фігурами (як scripts/green_frame.sh), запускає
+// анімацію і зберігає отриманий малюнок у SVG файл.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter/client"
+)
+
+func main() {
+	addr := flag.String("addr", "http://localhost:17000", "адреса painter")
+	out := flag.String("out", "frame.svg", "файл для збереження малюнку")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	c := client.New(*addr)
+
+	// Усі команди надсилаються одним запитом.
+	err := c.Send(ctx,
+		client.Reset(),
+		client.BgRect(0.25, 0.25, 0.75, 0.75),
+		client.Figure(0.5, 0.5),
+		client.Green(),
+		client.Figure(0.6, 0.6),
+		client.Update(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Неправильна команда повертає помилку з номером команди в пакеті.
+	err = c.Send(ctx, client.Move(0.1, 0.1), client.Move(2, 0))
+	var painterErr *client.Error
+	if errors.As(err, &painterErr) {
+		log.Printf("command %d rejected: %s", painterErr.Command, painterErr.Message)
+	}
+
+	if err := c.AnimateMove(ctx, -0.2, -0.2, time.Second, "ease-in-out"); err != nil {
+		log.Fatal(err)
+	}
+
+	frame, err := c.Frame(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, frame, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,33 +1,158 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
 	"net/http"
+	"os"
+	"strings"
 
 	"github.com/MytsV/architecture-lab-3/painter"
 	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/MytsV/architecture-lab-3/painter/rpc"
 	"github.com/MytsV/architecture-lab-3/ui"
+	"google.golang.org/grpc"
 )
 
 func main() {
-	var (
-		pv ui.Visualizer // Візуалізатор створює вікно та малює у ньому.
+	// Підкоманда "svg [файл]" виконує скрипт без вікна і виводить малюнок у SVG форматі.
+	if len(os.Args) > 1 && os.Args[1] == "svg" {
+		if err := exportSVG(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	listen := flag.String("listen", "", "додатково приймати команди рядками через сокет, наприклад tcp:localhost:17001 або unix:/tmp/painter.sock")
+	grpcAddr := flag.String("grpc", "", "додатково надавати gRPC сервіс Painter за адресою, наприклад localhost:17002")
+	scale := flag.String("scale", string(ui.ScaleStretch), "масштабування малюнку у вікні: stretch, fit, fill або integer")
+	record := flag.String("record", "", "зберігати кадри основної сесії у PNG файли за шаблоном, наприклад frames/%05d.png")
+	var windows sessionNames
+	flag.Var(&windows, "window", "відкрити ще одне вікно для сесії з вказаною назвою; можна вказати кілька разів. "+
+		"Сесія main показується завжди, інші сесії приймають команди за адресою /sessions/<назва>/")
+	flag.Parse()
 
-		// Потрібні для частини 2.
-		opLoop painter.Loop // Цикл обробки команд.
-		parser lang.Parser  // Парсер команд.
+	scaleMode, err := ui.ParseScaleMode(*scale)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Кожна сесія має власний малюнок; вікна з однаковою назвою сесії показують той самий малюнок.
+	sessions := map[string]*session{}
+	var (
+		order []*session
+		pvs   []*ui.Visualizer // Візуалізатори створюють вікна та малюють у них.
 	)
+	for _, name := range append(sessionNames{mainSession}, windows...) {
+		s, ok := sessions[name]
+		if !ok {
+			s = newSession(name)
+			sessions[name] = s
+			order = append(order, s)
+		}
+		pvs = append(pvs, s.window(scaleMode))
+	}
+	for _, s := range order {
+		s.loop.Receiver = s.receivers()
+	}
 
-	//pv.Debug = true
-	pv.Title = "Simple painter"
+	primary := sessions[mainSession]
+	var rpcServer rpc.Server // Типізований gRPC доступ до операцій малювання.
+	rpcServer.Loop = &primary.loop
+	rpcServer.Parser = &primary.parser
+	var recorder *painter.PNGRecorder
+	primary.loop.Receiver = rpcServer.Notify(primary.receivers())
+	if *record != "" {
+		recorder = &painter.PNGRecorder{Path: *record}
+		primary.loop.Receiver = painter.Receivers{primary.loop.Receiver, recorder}
+	}
 
-	pv.OnScreenReady = opLoop.Start
-	opLoop.Receiver = &pv
+	if *listen != "" {
+		l, err := listenLines(*listen)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer l.Close()
+		go func() {
+			_ = lang.ServeListener(l, &primary.loop, &primary.parser)
+		}()
+	}
+
+	if *grpcAddr != "" {
+		l, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		gs := grpc.NewServer()
+		rpc.RegisterPainterServer(gs, &rpcServer)
+		defer gs.Stop()
+		go func() {
+			_ = gs.Serve(l)
+		}()
+	}
 
 	go func() {
-		http.Handle("/", lang.HttpHandler(&opLoop, &parser))
+		for _, s := range order {
+			if s.name != mainSession {
+				prefix := "/sessions/" + s.name
+				http.Handle(prefix+"/", http.StripPrefix(prefix, s.handler()))
+			}
+		}
+		http.Handle("/", primary.handler())
 		_ = http.ListenAndServe("localhost:17000", nil)
 	}()
 
-	pv.Main()
-	opLoop.StopAndWait()
+	ui.Main(pvs...)
+	for _, s := range order {
+		s.stop()
+	}
+	if recorder != nil {
+		recorder.Close()
+	}
+}
+
+// exportSVG читає скрипт з файлу (або зі стандартного вводу, якщо файл не вказано) і записує отриманий малюнок у stdout.
+func exportSVG(args []string) error {
+	var in io.Reader = os.Stdin
+	if len(args) > 0 {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var parser lang.Parser
+	if _, err := parser.Parse(in); err != nil {
+		return err
+	}
+	return painter.ExportSVG(os.Stdout, parser.State())
+}
+
+// listenLines відкриває сокет за адресою у форматі "мережа:адреса", де мережа - tcp або unix.
+func listenLines(addr string) (net.Listener, error) {
+	network, address, ok := strings.Cut(addr, ":")
+	if !ok || (network != "tcp" && network != "unix") {
+		return nil, fmt.Errorf("invalid listen address %q, expected tcp:<host:port> or unix:<path>", addr)
+	}
+	return net.Listen(network, address)
+}
+
+// sessionNames - список назв сесій з прапорця -window.
+type sessionNames []string
+
+func (n *sessionNames) String() string {
+	return strings.Join(*n, ",")
+}
+
+func (n *sessionNames) Set(name string) error {
+	if name == "" || strings.ContainsAny(name, "/ ") {
+		return fmt.Errorf("invalid session name %q", name)
+	}
+	*n = append(*n, name)
+	return nil
 }
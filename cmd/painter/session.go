@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/MytsV/architecture-lab-3/ui"
+)
+
+// mainSession - назва сесії, команди якої приймаються за кореневими HTTP адресами, через сокет та gRPC.
+const mainSession = "main"
+
+// session - окремий малюнок зі своїм циклом подій, парсером та вікнами, що його показують.
+type session struct {
+	name string
+
+	loop      painter.Loop     // Цикл обробки команд.
+	parser    lang.Parser      // Парсер команд.
+	animator  painter.Animator // Програє анімації у циклі обробки команд.
+	scheduler lang.Scheduler   // Виконує відкладені та періодичні команди.
+	events    lang.Events      // Розсилає події вікон HTTP клієнтам.
+
+	windows []*ui.Visualizer
+}
+
+func newSession(name string) *session {
+	s := &session{name: name}
+	s.animator.Loop = &s.loop
+	s.animator.State = &s.parser
+	s.parser.Animator = &s.animator
+	s.scheduler.Loop = &s.loop
+	s.scheduler.Parser = &s.parser
+	s.parser.Scheduler = &s.scheduler
+	return s
+}
+
+// window створює ще одне вікно, яке показує малюнок сесії. Цикл подій запускається першим вікном.
+func (s *session) window(scale ui.ScaleMode) *ui.Visualizer {
+	pv := &ui.Visualizer{Title: "Simple painter", Scale: scale}
+	if s.name != mainSession {
+		pv.Title += " - " + s.name
+	}
+	//pv.Debug = true
+
+	if len(s.windows) == 0 {
+		pv.OnScreenReady = s.loop.Start
+	}
+	pv.Editor = lang.Editor{Loop: &s.loop, Parser: &s.parser}
+	pv.OnEvent = func(e ui.Event) { s.events.Publish(e) }
+	pv.DebugSource = func(info *ui.DebugInfo) {
+		info.QueueLen = s.loop.QueueLen()
+		info.LastCommand = s.parser.LastCommand()
+	}
+	s.windows = append(s.windows, pv)
+	return pv
+}
+
+// receivers повертає вікна сесії як отримувачів текстур циклу подій.
+func (s *session) receivers() painter.Receivers {
+	res := make(painter.Receivers, len(s.windows))
+	for idx, pv := range s.windows {
+		res[idx] = pv
+	}
+	return res
+}
+
+// handler повертає HTTP API сесії.
+func (s *session) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/frame.svg", lang.SVGHandler(&s.parser))
+	mux.Handle("/jobs", lang.JobsHandler(&s.scheduler))
+	mux.Handle("/jobs/", lang.JobsHandler(&s.scheduler))
+	mux.Handle("/macros", lang.MacrosHandler(&s.parser))
+	mux.Handle("/macros/", lang.MacrosHandler(&s.parser))
+	mux.Handle("/commands", lang.CommandsHandler(lang.DefaultRegistry))
+	mux.Handle("/commands/", lang.CommandsHandler(lang.DefaultRegistry))
+	mux.Handle("/events", lang.EventsHandler(&s.events))
+	mux.Handle("/ws", lang.WebSocketHandler(&s.loop, &s.parser))
+	mux.Handle("/", lang.HttpHandler(&s.loop, &s.parser))
+	return mux
+}
+
+// stop скасовує заплановані команди та анімації і зупиняє цикл подій.
+func (s *session) stop() {
+	s.scheduler.CancelAll()
+	s.animator.Cancel()
+	s.animator.Wait()
+	s.loop.StopAndWait()
+}
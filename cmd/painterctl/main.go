@@ -0,0 +1,154 @@
+// painterctl - інтерактивний клієнт для painter. Без аргументів запускає REPL, у якому кожен введений рядок
+// надсилається як команда скрипту. Також уміє виконувати файли зі скриптами та перезапускати їх при зміні:
+//
+//	painterctl [-addr URL]               - REPL
+//	painterctl [-addr URL] run FILE...   - виконати скрипти
+//	painterctl [-addr URL] watch FILE    - виконувати скрипт після кожної зміни файлу
+//
+// Прапорець -session задає сесію, у якій зберігаються макроси, описані командою "def".
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MytsV/architecture-lab-3/painter/client"
+)
+
+func main() {
+	addr := flag.String("addr", "http://localhost:17000", "адреса painter")
+	session := flag.String("session", "", "сесія для макросів; порожнє значення означає сесію за замовчуванням")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-addr URL] [-session ID] [run FILE... | watch FILE]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	c := client.New(*addr)
+	c.Session = *session
+	ctl := &controller{client: c, out: os.Stdout}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	switch flag.Arg(0) {
+	case "":
+		err = ctl.repl()
+	case "run":
+		if flag.NArg() < 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		for _, name := range flag.Args()[1:] {
+			if err = ctl.runFile(ctx, name); err != nil {
+				break
+			}
+		}
+	case "watch":
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		err = ctl.watch(ctx, flag.Arg(1))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		os.Exit(1)
+	}
+}
+
+// controller надсилає скрипти у painter і друкує результати.
+type controller struct {
+	client *client.Client
+	out    io.Writer
+
+	cancelWatch context.CancelFunc // зупиняє фонове спостереження за файлом у REPL
+}
+
+// runScript надсилає скрипт одним запитом і друкує текст, який повернув painter. У разі помилки друкує рядок, у
+// якому вона виникла, з позначкою місця.
+func (c *controller) runScript(ctx context.Context, source, script string) error {
+	if strings.TrimSpace(script) == "" {
+		return nil
+	}
+
+	out, err := c.client.SendScript(ctx, script)
+	if err == nil {
+		fmt.Fprint(c.out, out)
+		return nil
+	}
+
+	var painterErr *client.Error
+	lines := strings.Split(script, "\n")
+	if !errors.As(err, &painterErr) || painterErr.Line < 1 || painterErr.Line > len(lines) {
+		fmt.Fprintf(c.out, "%s: %s\n", source, err)
+		return err
+	}
+	fmt.Fprint(c.out, formatError(source, painterErr.Line, painterErr.Column, lines[painterErr.Line-1], painterErr.Message))
+	return err
+}
+
+func (c *controller) runFile(ctx context.Context, name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		fmt.Fprintln(c.out, err)
+		return err
+	}
+	return c.runScript(ctx, name, string(data))
+}
+
+// watch виконує файл і повторює виконання щоразу, коли змінюється час його модифікації, доки ctx не буде скасований.
+func (c *controller) watch(ctx context.Context, name string) error {
+	var last time.Time
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		info, err := os.Stat(name)
+		if err != nil {
+			fmt.Fprintln(c.out, err)
+		} else if !info.ModTime().Equal(last) {
+			last = info.ModTime()
+			if c.runFile(ctx, name) == nil {
+				fmt.Fprintf(c.out, "%s: OK\n", name)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// formatError форматує помилку у вигляді "джерело:рядок:символ: повідомлення" разом з текстом рядка, під яким
+// позначкою "^" вказане місце помилки.
+func formatError(source string, lineNo, col int, line, msg string) string {
+	line = strings.TrimRight(line, "\r")
+	res := fmt.Sprintf("%s:%d:%d: %s\n", source, lineNo, col, msg)
+	if col < 1 || col > utf8.RuneCountInString(line)+1 {
+		return res
+	}
+	// Табуляції зберігаються, щоб позначка стояла під тим самим символом.
+	var pad strings.Builder
+	for _, r := range []rune(line)[:col-1] {
+		if r == '\t' {
+			pad.WriteRune(r)
+		} else {
+			pad.WriteByte(' ')
+		}
+	}
+	return res + "\t" + line + "\n\t" + pad.String() + "^\n"
+}
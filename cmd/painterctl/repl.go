@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/peterh/liner"
+)
+
+// replCommands містить службові команди REPL, які не надсилаються у painter.
+var replCommands = []string{".run", ".watch", ".unwatch", ".help", ".exit"}
+
+const replHelp = `Кожен введений рядок надсилається у painter як команда скрипту.
+Службові команди:
+  .run FILE     виконати скрипт з файлу
+  .watch FILE   виконувати скрипт після кожної зміни файлу
+  .unwatch      припинити спостереження за файлом
+  .help         показати цю довідку
+  .exit         вийти (також Ctrl-D)
+
+Команди painter:
+`
+
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".painterctl_history")
+}
+
+// repl читає команди з терміналу з підтримкою редагування рядка, історії та доповнення назв команд клавішею Tab.
+func (c *controller) repl() error {
+	line := liner.NewLiner()
+	defer line.Close()
+	line.SetCtrlCAborts(true)
+	line.SetCompleter(complete)
+
+	history := historyPath()
+	if f, err := os.Open(history); err == nil {
+		_, _ = line.ReadHistory(f)
+		f.Close()
+	}
+	defer func() {
+		if f, err := os.Create(history); err == nil {
+			_, _ = line.WriteHistory(f)
+			f.Close()
+		}
+	}()
+
+	defer c.stopWatch()
+
+	for {
+		input, err := line.Prompt("painter> ")
+		if errors.Is(err, liner.ErrPromptAborted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(c.out)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+		line.AppendHistory(input)
+
+		fields := strings.Fields(input)
+		switch fields[0] {
+		case ".exit":
+			return nil
+		case ".help":
+			fmt.Fprint(c.out, replHelp+lang.DefaultRegistry.Help())
+		case ".run":
+			for _, name := range fields[1:] {
+				_ = c.runFile(context.Background(), name)
+			}
+		case ".watch":
+			if len(fields) != 2 {
+				fmt.Fprintln(c.out, "usage: .watch FILE")
+				continue
+			}
+			c.startWatch(fields[1])
+		case ".unwatch":
+			c.stopWatch()
+		default:
+			_ = c.runScript(context.Background(), "input", input)
+		}
+	}
+}
+
+// startWatch запускає спостереження за файлом у фоні, зупиняючи попереднє.
+func (c *controller) startWatch(name string) {
+	c.stopWatch()
+	var ctx context.Context
+	ctx, c.cancelWatch = context.WithCancel(context.Background())
+	go func() { _ = c.watch(ctx, name) }()
+}
+
+func (c *controller) stopWatch() {
+	if c.cancelWatch != nil {
+		c.cancelWatch()
+		c.cancelWatch = nil
+	}
+}
+
+// complete доповнює назву команди на початку рядка.
+func complete(line string) []string {
+	if strings.ContainsAny(line, " \t") {
+		return nil
+	}
+	var res []string
+	for _, name := range append(lang.CommandNames(), replCommands...) {
+		if strings.HasPrefix(name, line) {
+			res = append(res, name+" ")
+		}
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,228 @@
+package painter
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/shiny/screen"
+)
+
+// StateHolder зберігає стан малюнку і дозволяє безпечно змінювати його з інших горутин.
+type StateHolder interface {
+	// Tweak застосовує зміну до стану і повертає операцію, що малює новий стан.
+	Tweak(t StateTweaker) Operation
+}
+
+// Easing перетворює частку часу анімації з [0,1] у частку виконаної зміни з [0,1].
+type Easing func(t float64) float64
+
+// Easings містить підтримувані функції пом'якшення анімації за назвами.
+var Easings = map[string]Easing{
+	"linear":      func(t float64) float64 { return t },
+	"ease-in":     func(t float64) float64 { return t * t },
+	"ease-out":    func(t float64) float64 { return 1 - (1-t)*(1-t) },
+	"ease-in-out": func(t float64) float64 { return (1 - math.Cos(math.Pi*t)) / 2 },
+}
+
+// Animation описує плавну зміну стану малюнку протягом часу.
+type Animation struct {
+	Duration time.Duration
+	Easing   Easing
+	// Step повертає зміну стану, яка переводить малюнок з прогресу from у прогрес to.
+	Step func(from, to float64) StateTweaker
+}
+
+// MoveAnimation створює анімацію переміщення фігур на зміщення offset.
+func MoveAnimation(offset RelativePoint, d time.Duration, e Easing) Animation {
+	return Animation{
+		Duration: d,
+		Easing:   e,
+		Step: func(from, to float64) StateTweaker {
+			return MoveTweaker{Offset: RelativePoint{
+				X: offset.X * (to - from),
+				Y: offset.Y * (to - from),
+			}}
+		},
+	}
+}
+
+// RotateAnimation створює анімацію повороту фігур на кут angle у радіанах. Якщо pivot не вказаний, кожна фігура
+// повертається навколо власного центру.
+func RotateAnimation(angle float64, pivot *RelativePoint, d time.Duration, e Easing) Animation {
+	return Animation{
+		Duration: d,
+		Easing:   e,
+		Step: func(from, to float64) StateTweaker {
+			return TransformTweaker{Matrix: Rotation(angle * (to - from)), Pivot: pivot}
+		},
+	}
+}
+
+// ScaleAnimation створює анімацію масштабування фігур з коефіцієнтом factor. Масштаб змінюється геометрично, тому
+// кроки анімації перемножуються у factor незалежно від їх кількості.
+func ScaleAnimation(factor float64, pivot *RelativePoint, d time.Duration, e Easing) Animation {
+	return Animation{
+		Duration: d,
+		Easing:   e,
+		Step: func(from, to float64) StateTweaker {
+			return TransformTweaker{Matrix: Scaling(math.Pow(factor, to-from)), Pivot: pivot}
+		},
+	}
+}
+
+const defaultFrameInterval = 16 * time.Millisecond
+
+// Animator програє анімації по черзі, надсилаючи кадри у Loop. Кожна наступна анімація починається після завершення
+// попередньої, що дозволяє будувати ланцюжки.
+type Animator struct {
+	Loop  *Loop
+	State StateHolder
+	// FrameInterval задає проміжок між кадрами. Якщо не вказаний, використовується 16мс.
+	FrameInterval time.Duration
+
+	mu      sync.Mutex
+	queue   []Animation
+	cancel  chan struct{} // закривається для скасування програвання; nil, якщо програвання не відбувається
+	running chan struct{} // закривається, коли горутина програвання завершила роботу
+}
+
+// Start додає анімацію в кінець черги і запускає програвання, якщо воно ще не відбувається.
+func (a *Animator) Start(anim Animation) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.queue = append(a.queue, anim)
+	if a.cancel == nil {
+		a.cancel = make(chan struct{})
+		// Нова горутина дочекається завершення попередньої, якщо та ще не встигла зупинитися після скасування.
+		prev := a.running
+		a.running = make(chan struct{})
+		go a.run(prev, a.cancel, a.running)
+	}
+}
+
+// Cancel зупиняє поточну анімацію та видаляє всі анімації, що чекають у черзі. Стан залишається таким, яким він був
+// на момент зупинки.
+func (a *Animator) Cancel() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.queue = nil
+	if a.cancel != nil {
+		close(a.cancel)
+		a.cancel = nil
+	}
+}
+
+// Wait чекає, поки всі анімації в черзі будуть програні або скасовані.
+func (a *Animator) Wait() {
+	for {
+		a.mu.Lock()
+		running := a.running
+		a.mu.Unlock()
+
+		if running == nil {
+			return
+		}
+		<-running
+	}
+}
+
+func (a *Animator) run(prev, cancel, running chan struct{}) {
+	if prev != nil {
+		<-prev
+	}
+
+	for {
+		a.mu.Lock()
+		if len(a.queue) == 0 || isClosed(cancel) {
+			// Завершуємо роботу під тим самим блокуванням, щоб Start не додав анімацію, яку вже ніхто не програє.
+			if a.cancel == cancel {
+				a.cancel = nil
+			}
+			if a.running == running {
+				a.running = nil
+			}
+			close(running)
+			a.mu.Unlock()
+			return
+		}
+		anim := a.queue[0]
+		a.queue = a.queue[1:]
+		a.mu.Unlock()
+
+		if !a.play(anim, cancel) {
+			a.Cancel()
+		}
+	}
+}
+
+// play програє одну анімацію. Повертає false, якщо кадр не вдалося надіслати у цикл подій.
+func (a *Animator) play(anim Animation, cancel chan struct{}) bool {
+	interval := a.FrameInterval
+	if interval <= 0 {
+		interval = defaultFrameInterval
+	}
+	easing := anim.Easing
+	if easing == nil {
+		easing = Easings["linear"]
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	prev := 0.0
+	for {
+		select {
+		case <-cancel:
+			return true
+		case now := <-ticker.C:
+			progress := 1.0
+			if anim.Duration > 0 {
+				progress = math.Min(1, float64(now.Sub(start))/float64(anim.Duration))
+			}
+			eased := easing(progress)
+			op := a.State.Tweak(anim.Step(prev, eased))
+			prev = eased
+
+			if a.Loop.Post(op) != nil || a.Loop.Post(UpdateOp) != nil {
+				return false
+			}
+			if progress >= 1 {
+				return true
+			}
+		}
+	}
+}
+
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// AnimateOp передає анімацію в Animator, коли черга циклу подій доходить до неї.
+type AnimateOp struct {
+	Animator  *Animator
+	Animation Animation
+}
+
+func (op AnimateOp) Do(t screen.Texture) bool {
+	op.Animator.Start(op.Animation)
+	return false
+}
+
+// CancelAnimationsOp скасовує всі анімації Animator, коли черга циклу подій доходить до неї.
+type CancelAnimationsOp struct {
+	Animator *Animator
+}
+
+func (op CancelAnimationsOp) Do(t screen.Texture) bool {
+	op.Animator.Cancel()
+	return false
+}
@@ -0,0 +1,164 @@
+package painter
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/screen"
+)
+
+// BlendMode задає, як колір операції поєднується з уже намальованим на текстурі.
+type BlendMode string
+
+const (
+	// BlendOver накладає колір з урахуванням його прозорості. Використовується за замовчуванням.
+	BlendOver BlendMode = "over"
+	// BlendSrc замінює пікселі текстури кольором операції разом з його прозорістю.
+	BlendSrc BlendMode = "src"
+	// BlendMultiply перемножує кольори, тому результат завжди темніший за обидва.
+	BlendMultiply BlendMode = "multiply"
+	// BlendScreen перемножує доповнення кольорів, тому результат завжди світліший за обидва.
+	BlendScreen BlendMode = "screen"
+	// BlendXor залишає лише ті частини кольорів, що не перекриваються (xor з Porter-Duff).
+	BlendXor BlendMode = "xor"
+)
+
+// BlendModes містить усі підтримувані режими у порядку, в якому вони показуються у довідці.
+var BlendModes = []BlendMode{BlendOver, BlendSrc, BlendMultiply, BlendScreen, BlendXor}
+
+// software повідомляє, чи потребує режим читання пікселів текстури. screen.Texture вміє лише draw.Src та draw.Over,
+// тому такі режими виконуються програмно (див. Canvas).
+func (m BlendMode) software() bool {
+	return m == BlendMultiply || m == BlendScreen || m == BlendXor
+}
+
+// drawOp повертає операцію screen.Texture, найближчу до режиму.
+func (m BlendMode) drawOp() draw.Op {
+	if m == BlendSrc {
+		return draw.Src
+	}
+	return draw.Over
+}
+
+// blend поєднує канал кольору операції s з каналом текстури d. Значення каналів та прозорості sa, da передаються
+// помноженими на прозорість і нормованими до [0,1].
+func (m BlendMode) blend(s, d, sa, da float64) float64 {
+	switch m {
+	case BlendSrc:
+		return s
+	case BlendMultiply:
+		return s*(1-da) + d*(1-sa) + s*d
+	case BlendScreen:
+		return s + d - s*d
+	case BlendXor:
+		return s*(1-da) + d*(1-sa)
+	default:
+		return s + d*(1-sa)
+	}
+}
+
+// fillRect зафарбовує прямокутник кольором c у режимі m. Програмні режими виконуються лише на Canvas, на інших
+// текстурах та вікнах вони замінюються на BlendOver.
+func fillRect(t screen.Uploader, r image.Rectangle, c color.Color, m BlendMode) {
+	canvas, ok := t.(*Canvas)
+	if !ok || !m.software() {
+		t.Fill(r, c, m.drawOp())
+		return
+	}
+
+	r = r.Intersect(canvas.Rect)
+	sr, sg, sb, sa := c.RGBA()
+	const max = 0xffff
+	s := [4]float64{float64(sr) / max, float64(sg) / max, float64(sb) / max, float64(sa) / max}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			px := canvas.RGBAAt(x, y)
+			d := [4]float64{float64(px.R) / 0xff, float64(px.G) / 0xff, float64(px.B) / 0xff, float64(px.A) / 0xff}
+			var res [4]uint8
+			for idx := range res {
+				v := m.blend(s[idx], d[idx], s[3], d[3])
+				res[idx] = uint8(clamp01(v)*0xff + 0.5)
+			}
+			canvas.SetRGBA(x, y, color.RGBA{R: res[0], G: res[1], B: res[2], A: res[3]})
+		}
+	}
+}
+
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// Canvas - текстура у пам'яті, з якої можна читати пікселі. Використовується для програмного малювання, результат
+// якого завантажується у справжню текстуру через Upload.
+type Canvas struct {
+	*image.RGBA
+}
+
+// NewCanvas створює прозору текстуру у пам'яті заданого розміру.
+func NewCanvas(size image.Point) *Canvas {
+	return &Canvas{RGBA: image.NewRGBA(image.Rectangle{Max: size})}
+}
+
+func (c *Canvas) Release() {}
+
+func (c *Canvas) Size() image.Point { return c.Rect.Size() }
+
+func (c *Canvas) Upload(dp image.Point, src screen.Buffer, sr image.Rectangle) {
+	draw.Draw(c.RGBA, sr.Sub(sr.Min).Add(dp), src.RGBA(), sr.Min, draw.Src)
+}
+
+func (c *Canvas) Fill(dr image.Rectangle, src color.Color, op draw.Op) {
+	draw.Draw(c.RGBA, dr, image.NewUniform(src), image.Point{}, op)
+}
+
+// BufferProvider створює буфери для завантаження зображень у текстуру. Текстури, які Loop передає операціям,
+// реалізують цей інтерфейс.
+type BufferProvider interface {
+	NewBuffer(size image.Point) (screen.Buffer, error)
+}
+
+// loopTexture - текстура циклу подій разом з екраном, що дозволяє операціям створювати буфери.
+type loopTexture struct {
+	screen.Texture
+	screen screen.Screen
+}
+
+func (t loopTexture) NewBuffer(size image.Point) (screen.Buffer, error) {
+	return t.screen.NewBuffer(size)
+}
+
+// canUpload перевіряє, чи можна завантажити у текстуру зображення, намальоване програмно.
+func canUpload(t screen.Texture) bool {
+	switch t.(type) {
+	case draw.Image, BufferProvider:
+		return true
+	}
+	return false
+}
+
+// upload переносить зображення у текстуру. Для текстур у пам'яті зображення копіюється напряму, для інших
+// створюється тимчасовий буфер. Повертає false, якщо буфер створити не вдалося.
+func upload(t screen.Texture, img *image.RGBA) bool {
+	switch t := t.(type) {
+	case draw.Image:
+		draw.Draw(t, img.Rect, img, image.Point{}, draw.Src)
+		return true
+	case BufferProvider:
+		b, err := t.NewBuffer(img.Rect.Size())
+		if err != nil || b == nil {
+			return false
+		}
+		defer b.Release()
+		draw.Draw(b.RGBA(), b.Bounds(), img, image.Point{}, draw.Src)
+		t.(screen.Texture).Upload(image.Point{}, b, b.Bounds())
+		return true
+	}
+	return false
+}
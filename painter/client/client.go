@@ -0,0 +1,470 @@
+// Package client надає Go клієнт для HTTP API painter, щоб сервісам не доводилося формувати запити вручну.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+)
+
+// Command описує одну команду скрипту. Команди створюються функціями цього пакету, наприклад Figure або Move.
+type Command struct {
+	Op   string
+	Args []any
+	// Named містить іменовані аргументи. Painter не дозволяє поєднувати їх з позиційними аргументами Args.
+	Named map[string]any
+}
+
+func (c Command) MarshalJSON() ([]byte, error) {
+	fields := map[string]any{"op": c.Op}
+	for name, value := range c.Named {
+		fields[name] = value
+	}
+	if len(c.Args) > 0 {
+		fields["args"] = c.Args
+	}
+	return json.Marshal(fields)
+}
+
+func White() Command  { return Command{Op: "white"} }
+func Green() Command  { return Command{Op: "green"} }
+func Update() Command { return Command{Op: "update"} }
+func Reset() Command  { return Command{Op: "reset"} }
+
+func BgRect(x1, y1, x2, y2 float64) Command {
+	return Command{Op: "bgrect", Args: []any{x1, y1, x2, y2}}
+}
+
+// RectOptions задає необов'язкові параметри прямокутника фону для BgRectWith. Нульові значення означають значення
+// за замовчуванням команди "bgrect".
+type RectOptions struct {
+	// Color - колір прямокутника; напівпрозорі кольори накладаються на фон.
+	Color color.Color
+	// Blend - режим накладання, наприклад "over" або "xor".
+	Blend string
+	// Border - товщина рамки у частках ширини текстури, BorderColor - її колір.
+	Border      float64
+	BorderColor color.Color
+	// Replace видаляє всі попередні прямокутники фону.
+	Replace bool
+}
+
+// BgRectWith малює прямокутник фону з параметрами opts. Аргументи передаються за назвою, тому вказуються лише задані
+// параметри.
+func BgRectWith(x1, y1, x2, y2 float64, opts RectOptions) Command {
+	named := map[string]any{"x1": x1, "y1": y1, "x2": x2, "y2": y2}
+	if opts.Color != nil {
+		named["color"] = colorValue(opts.Color)
+	}
+	if opts.Blend != "" {
+		named["blend"] = opts.Blend
+	}
+	if opts.Border > 0 {
+		named["border"] = opts.Border
+	}
+	if opts.BorderColor != nil {
+		named["border_color"] = colorValue(opts.BorderColor)
+	}
+	if opts.Replace {
+		named["mode"] = "replace"
+	}
+	return Command{Op: "bgrect", Named: named}
+}
+
+func Figure(x, y float64) Command {
+	return Command{Op: "figure", Args: []any{x, y}}
+}
+
+func Move(dx, dy float64) Command {
+	return Command{Op: "move", Args: []any{dx, dy}}
+}
+
+// Rotate повертає всі фігури на angle градусів за годинниковою стрілкою навколо їх центрів.
+func Rotate(angle float64) Command {
+	return Command{Op: "rotate", Args: []any{angle}}
+}
+
+// RotateAround повертає всі фігури на angle градусів навколо точки cx cy.
+func RotateAround(angle, cx, cy float64) Command {
+	return Command{Op: "rotate", Args: []any{angle, cx, cy}}
+}
+
+// Scale масштабує всі фігури відносно їх центрів.
+func Scale(factor float64) Command {
+	return Command{Op: "scale", Args: []any{factor}}
+}
+
+// ScaleAround масштабує всі фігури відносно точки cx cy.
+func ScaleAround(factor, cx, cy float64) Command {
+	return Command{Op: "scale", Args: []any{factor, cx, cy}}
+}
+
+// LinearGradient зафарбовує фон градієнтом від кольору from у точці x1 y1 до кольору to у точці x2 y2.
+func LinearGradient(x1, y1, x2, y2 float64, from, to color.Color) Command {
+	return Command{Op: "gradient", Args: []any{"linear", x1, y1, x2, y2, colorValue(from), colorValue(to)}}
+}
+
+// RadialGradient зафарбовує фон градієнтом від кольору inner у центрі cx cy до кольору outer на колі з радіусом radius.
+func RadialGradient(cx, cy, radius float64, inner, outer color.Color) Command {
+	return Command{Op: "gradient", Args: []any{"radial", cx, cy, radius, colorValue(inner), colorValue(outer)}}
+}
+
+// Checkerboard зафарбовує фон шаховою дошкою з клітинками розміру cell.
+func Checkerboard(cell float64, c1, c2 color.Color) Command {
+	return Command{Op: "pattern", Args: []any{"checkerboard", cell, colorValue(c1), colorValue(c2)}}
+}
+
+// Stripes зафарбовує фон смугами ширини width, нахиленими на angle градусів.
+func Stripes(width float64, c1, c2 color.Color, angle float64) Command {
+	return Command{Op: "pattern", Args: []any{"stripes", width, colorValue(c1), colorValue(c2), angle}}
+}
+
+// RelativeCoords, PixelCoords та ViewportCoords задають систему координат для наступних команд (див. lang.CoordSystem).
+// Система координат спільна для всіх клієнтів painter.
+func RelativeCoords() Command { return Command{Op: "coords", Args: []any{"relative"}} }
+func PixelCoords() Command    { return Command{Op: "coords", Args: []any{"pixel"}} }
+
+func ViewportCoords(x1, y1, x2, y2 float64) Command {
+	return Command{Op: "coords", Args: []any{"viewport", x1, y1, x2, y2}}
+}
+
+// Undo повертає стан малюнку, який був до останнього скрипту, що його змінив.
+func Undo() Command { return Command{Op: "undo"} }
+
+// AnimateMove плавно зміщує фігури протягом d. Порожній easing означає лінійну анімацію.
+func AnimateMove(dx, dy float64, d time.Duration, easing string) Command {
+	args := []any{"move", dx, dy, d.Milliseconds()}
+	if easing != "" {
+		args = append(args, easing)
+	}
+	return Command{Op: "animate", Args: args}
+}
+
+func AnimateStop() Command {
+	return Command{Op: "animate", Args: []any{"stop"}}
+}
+
+// After виконує cmd один раз через проміжок d.
+func After(d time.Duration, cmd Command) Command {
+	return Command{Op: "after", Args: []any{d.String(), cmd.script()}}
+}
+
+// Every виконує cmd кожні d.
+func Every(d time.Duration, cmd Command) Command {
+	return Command{Op: "every", Args: []any{d.String(), cmd.script()}}
+}
+
+// colorValue записує колір у форматі "#rrggbbaa", який розуміє painter.
+func colorValue(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+// script записує команду рядком текстового скрипту. "after" та "every" отримують заплановану команду одним рядком,
+// тому так передаються і позиційні, і іменовані аргументи.
+func (c Command) script() string {
+	fields := []string{c.Op}
+	for _, arg := range c.Args {
+		fields = append(fields, scriptValue(arg))
+	}
+	names := make([]string, 0, len(c.Named))
+	for name := range c.Named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fields = append(fields, name+"="+scriptValue(c.Named[name]))
+	}
+	return strings.Join(fields, " ")
+}
+
+// scriptValue записує аргумент так само, як painter записує аргументи JSON команд: рядки беруться в лапки, а числа
+// записуються без експоненти.
+func scriptValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return lang.Quote(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Raw створює довільну команду з текстовою назвою та аргументами, наприклад для команд, введених користувачем.
+func Raw(op string, args ...any) Command {
+	return Command{Op: op, Args: args}
+}
+
+// Error описує відповідь painter з помилкою.
+type Error struct {
+	StatusCode int
+	Message    string
+	// Command містить номер команди з пакету, яка не пройшла перевірку, або -1, якщо помилка не стосується окремої
+	// команди.
+	Command int
+	// Line та Column містять позицію помилки у текстовому скрипті, надісланому через SendScript, або 0.
+	Line, Column int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("painter: %s (HTTP %d)", e.Message, e.StatusCode)
+}
+
+// Client надсилає команди у painter через HTTP.
+type Client struct {
+	// BaseURL задає адресу painter, наприклад "http://localhost:17000".
+	BaseURL string
+	// HTTPClient використовується для запитів. Якщо не вказаний, використовується http.DefaultClient.
+	HTTPClient *http.Client
+	// Session задає сесію, у якій виконуються скрипти клієнта (див. lang.Session). Макроси, описані в одній сесії, не
+	// видно в інших. Якщо не вказана, використовується сесія за замовчуванням.
+	Session string
+}
+
+// New створює клієнт для painter за адресою baseURL.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// SendScript надсилає текстовий скрипт одним запитом. Якщо скрипт містить помилку, жодна з команд не виконується, а
+// повертається *Error з позицією помилки. Повертає текст, який painter надіслав у відповідь (наприклад, результат
+// "help" чи "coords"), або порожній рядок, якщо скрипт нічого не виводить.
+func (c *Client) SendScript(ctx context.Context, script string) (string, error) {
+	resp, err := c.do(ctx, http.MethodPost, "/", "text/plain", strings.NewReader(script))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	out, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// Send надсилає команди одним запитом. Якщо хоча б одна команда не проходить перевірку, жодна з них не виконується,
+// а повертається *Error з номером неправильної команди.
+func (c *Client) Send(ctx context.Context, cmds ...Command) error {
+	body, err := json.Marshal(cmds)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (c *Client) White(ctx context.Context) error  { return c.Send(ctx, White()) }
+func (c *Client) Green(ctx context.Context) error  { return c.Send(ctx, Green()) }
+func (c *Client) Update(ctx context.Context) error { return c.Send(ctx, Update()) }
+func (c *Client) Reset(ctx context.Context) error  { return c.Send(ctx, Reset()) }
+
+func (c *Client) BgRect(ctx context.Context, x1, y1, x2, y2 float64) error {
+	return c.Send(ctx, BgRect(x1, y1, x2, y2))
+}
+
+func (c *Client) Figure(ctx context.Context, x, y float64) error {
+	return c.Send(ctx, Figure(x, y))
+}
+
+func (c *Client) Move(ctx context.Context, dx, dy float64) error {
+	return c.Send(ctx, Move(dx, dy))
+}
+
+func (c *Client) BgRectWith(ctx context.Context, x1, y1, x2, y2 float64, opts RectOptions) error {
+	return c.Send(ctx, BgRectWith(x1, y1, x2, y2, opts))
+}
+
+func (c *Client) Rotate(ctx context.Context, angle float64) error {
+	return c.Send(ctx, Rotate(angle))
+}
+
+func (c *Client) Scale(ctx context.Context, factor float64) error {
+	return c.Send(ctx, Scale(factor))
+}
+
+func (c *Client) LinearGradient(ctx context.Context, x1, y1, x2, y2 float64, from, to color.Color) error {
+	return c.Send(ctx, LinearGradient(x1, y1, x2, y2, from, to))
+}
+
+func (c *Client) RadialGradient(ctx context.Context, cx, cy, radius float64, inner, outer color.Color) error {
+	return c.Send(ctx, RadialGradient(cx, cy, radius, inner, outer))
+}
+
+func (c *Client) Checkerboard(ctx context.Context, cell float64, c1, c2 color.Color) error {
+	return c.Send(ctx, Checkerboard(cell, c1, c2))
+}
+
+func (c *Client) Stripes(ctx context.Context, width float64, c1, c2 color.Color, angle float64) error {
+	return c.Send(ctx, Stripes(width, c1, c2, angle))
+}
+
+func (c *Client) Undo(ctx context.Context) error { return c.Send(ctx, Undo()) }
+
+func (c *Client) AnimateMove(ctx context.Context, dx, dy float64, d time.Duration, easing string) error {
+	return c.Send(ctx, AnimateMove(dx, dy, d, easing))
+}
+
+func (c *Client) AnimateStop(ctx context.Context) error {
+	return c.Send(ctx, AnimateStop())
+}
+
+func (c *Client) After(ctx context.Context, d time.Duration, cmd Command) error {
+	return c.Send(ctx, After(d, cmd))
+}
+
+func (c *Client) Every(ctx context.Context, d time.Duration, cmd Command) error {
+	return c.Send(ctx, Every(d, cmd))
+}
+
+// Frame завантажує поточний малюнок у форматі SVG.
+func (c *Client) Frame(ctx context.Context) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/frame.svg", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// Jobs повертає список запланованих завдань.
+func (c *Client) Jobs(ctx context.Context) ([]lang.Job, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/jobs", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var jobs []lang.Job
+	if err := json.NewDecoder(resp.Body).Decode(&jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// CancelJob скасовує заплановане завдання з ідентифікатором id.
+func (c *Client) CancelJob(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/jobs/%d", id), "", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// CancelJobs скасовує всі заплановані завдання.
+func (c *Client) CancelJobs(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodDelete, "/jobs", "", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Macros повертає макроси, описані у скриптах сесії клієнта командою "def".
+func (c *Client) Macros(ctx context.Context) ([]lang.Macro, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/macros", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var macros []lang.Macro
+	if err := json.NewDecoder(resp.Body).Decode(&macros); err != nil {
+		return nil, err
+	}
+	return macros, nil
+}
+
+// DeleteMacro видаляє макрос з назвою name.
+func (c *Client) DeleteMacro(ctx context.Context, name string) error {
+	resp, err := c.do(ctx, http.MethodDelete, "/macros/"+url.PathEscape(name), "", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// DeleteMacros видаляє всі макроси.
+func (c *Client) DeleteMacros(ctx context.Context) error {
+	resp, err := c.do(ctx, http.MethodDelete, "/macros", "", nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Commands повертає опис команд, які підтримує painter.
+func (c *Client) Commands(ctx context.Context) ([]lang.CommandInfo, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/commands", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var cmds []lang.CommandInfo
+	if err := json.NewDecoder(resp.Body).Decode(&cmds); err != nil {
+		return nil, err
+	}
+	return cmds, nil
+}
+
+// do виконує запит і перетворює відповідь з кодом помилки у *Error.
+func (c *Client) do(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.Session != "" {
+		req.Header.Set(lang.SessionHeader, c.Session)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 400 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	return nil, decodeError(resp)
+}
+
+func decodeError(resp *http.Response) error {
+	e := &Error{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode), Command: -1}
+
+	var body struct {
+		Error   string `json:"error"`
+		Command *int   `json:"command"`
+		Line    int    `json:"line"`
+		Column  int    `json:"column"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&body) == nil && body.Error != "" {
+		e.Message = body.Error
+		if body.Command != nil {
+			e.Command = *body.Command
+		}
+		e.Line, e.Column = body.Line, body.Column
+	}
+	return e
+}
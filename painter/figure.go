@@ -0,0 +1,70 @@
+package painter
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/exp/shiny/screen"
+)
+
+// Фігура - літера "Т" розміром 230x230 пікселів. Координати задаються у пікселях від лівого верхнього кута текстури
+// чи вікна, вісь Y спрямована вниз, тому перекладина літери знаходиться над центром.
+const (
+	figureHalfLength = 115
+	figureHalfWidth  = 35
+)
+
+// figureParts - прямокутники, з яких складається фігура, відносно її центру: перекладина та ніжка під нею. Частини
+// не перекриваються, як і частини OperationBGRect, тому напівпрозора фігура накладається на фон лише один раз.
+var figureParts = []image.Rectangle{
+	image.Rect(-figureHalfLength, -figureHalfLength, figureHalfLength, -figureHalfLength+figureHalfWidth*2),
+	image.Rect(-figureHalfWidth, -figureHalfLength+figureHalfWidth*2, figureHalfWidth, figureHalfLength),
+}
+
+// DrawFigure малює фігуру з центром center на dst, обрізаючи її прямокутником clip. Цю функцію використовують і
+// OperationFigure, і вікно ui.Visualizer, тому фігура виглядає однаково незалежно від того, де вона малюється.
+func DrawFigure(dst screen.Uploader, clip image.Rectangle, center image.Point, m Matrix, c color.Color, mode BlendMode) {
+	rects, polygons := figureShapes(center, m, clip)
+	for _, r := range rects {
+		fillRect(dst, r, c, mode)
+	}
+	fillPolygons(dst, polygons, clip, c, mode)
+}
+
+// figureShapes повертає частини фігури. Якщо перетворення зберігає напрямок сторін, частини залишаються
+// прямокутниками, обрізаними прямокутником clip, інакше - повертаються многокутниками. Частини, що повністю
+// знаходяться за межами clip, пропускаються. Фігура з перетворенням, яке не можна намалювати (див. Matrix.Drawable),
+// частин не має.
+func figureShapes(center image.Point, m Matrix, clip image.Rectangle) ([]image.Rectangle, []polygon) {
+	var (
+		rects    []image.Rectangle
+		polygons []polygon
+	)
+	if !m.Drawable() {
+		return nil, nil
+	}
+	for _, part := range figureParts {
+		p := transformRect(part, m, center)
+		if m.axisAligned() {
+			if r := p.rect().Intersect(clip); !r.Empty() {
+				rects = append(rects, r)
+			}
+		} else if p.bounds().Overlaps(clip) {
+			polygons = append(polygons, p)
+		}
+	}
+	return rects, polygons
+}
+
+// figureContains перевіряє, чи лежить точка (x, y) у фігурі з центром center.
+func figureContains(center image.Point, m Matrix, x, y float64) bool {
+	if !m.Drawable() {
+		return false
+	}
+	for _, part := range figureParts {
+		if transformRect(part, m, center).contains(x, y) {
+			return true
+		}
+	}
+	return false
+}
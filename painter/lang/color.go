@@ -0,0 +1,69 @@
+package lang
+
+import (
+	"encoding/hex"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// colorNames містить кольори, які можна вказати назвою.
+var colorNames = map[string]color.Color{
+	"black":       color.Black,
+	"white":       color.White,
+	"red":         color.RGBA{R: 0xff, A: 0xff},
+	"green":       color.RGBA{G: 0xff, A: 0xff},
+	"blue":        color.RGBA{B: 0xff, A: 0xff},
+	"yellow":      color.RGBA{R: 0xff, G: 0xff, A: 0xff},
+	"cyan":        color.RGBA{G: 0xff, B: 0xff, A: 0xff},
+	"magenta":     color.RGBA{R: 0xff, B: 0xff, A: 0xff},
+	"gray":        color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	"transparent": color.Transparent,
+}
+
+// parseColor розбирає колір у форматі аргументу Color. Оскільки "#" у скрипті починає коментар, шістнадцятковий
+// запис потрібно брати в лапки: "#ff8000".
+func parseColor(s string) (color.Color, bool) {
+	if c, ok := colorNames[strings.ToLower(s)]; ok {
+		return c, true
+	}
+	if digits, ok := strings.CutPrefix(s, "#"); ok {
+		b, err := hex.DecodeString(digits)
+		if err != nil || (len(b) != 3 && len(b) != 4) {
+			return nil, false
+		}
+		c := color.NRGBA{R: b[0], G: b[1], B: b[2], A: 0xff}
+		if len(b) == 4 {
+			c.A = b[3]
+		}
+		return c, true
+	}
+
+	name, rest, ok := strings.Cut(s, "(")
+	list, closed := strings.CutSuffix(rest, ")")
+	if !ok || !closed || (name != "rgb" && name != "rgba") {
+		return nil, false
+	}
+	parts := strings.Split(list, ",")
+	if len(parts) != len(name) {
+		return nil, false
+	}
+	var channels [3]uint8
+	for idx := range channels {
+		v, err := strconv.Atoi(strings.TrimSpace(parts[idx]))
+		if err != nil || v < 0 || v > 0xff {
+			return nil, false
+		}
+		channels[idx] = uint8(v)
+	}
+	c := color.NRGBA{R: channels[0], G: channels[1], B: channels[2], A: 0xff}
+	if name == "rgba" {
+		// Прозорість, як і в CSS, задається часткою з проміжку [0,1].
+		alpha, err := strconv.ParseFloat(strings.TrimSpace(parts[3]), 64)
+		if err != nil || alpha < 0 || alpha > 1 {
+			return nil, false
+		}
+		c.A = uint8(alpha*0xff + 0.5)
+	}
+	return c, true
+}
@@ -0,0 +1,439 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// builtinCommands повертає команди, які підтримує мова скриптів за замовчуванням.
+func builtinCommands() []Command {
+	return []Command{
+		{
+			Name:        "white",
+			Description: "Зафарбовує фон у білий колір.",
+			Tweak: func(args Args) painter.StateTweaker {
+				return painter.OperationFill{Color: color.White}
+			},
+		},
+		{
+			Name:        "green",
+			Description: "Зафарбовує фон у зелений колір.",
+			Tweak: func(args Args) painter.StateTweaker {
+				return painter.OperationFill{Color: color.RGBA{G: 0xff, A: 0xff}}
+			},
+		},
+		{
+			Name:           "gradient",
+			SubcommandKind: "gradient",
+			Subcommands: []Command{
+				{
+					Name:        "linear",
+					Description: "Зафарбовує фон градієнтом, що змінюється від точки x1 y1 до точки x2 y2.",
+					Args: []Arg{
+						Coord("x1", "початок градієнта по горизонталі", AxisX),
+						Coord("y1", "початок градієнта по вертикалі", AxisY),
+						Coord("x2", "кінець градієнта по горизонталі", AxisX),
+						Coord("y2", "кінець градієнта по вертикалі", AxisY),
+						{Name: "from", Type: Color, Description: "колір на початку"},
+						{Name: "to", Type: Color, Description: "колір в кінці"},
+					},
+					Tweak: func(args Args) painter.StateTweaker {
+						return painter.PatternFill(painter.LinearGradient{
+							From:      painter.RelativePoint{X: args.Float(0), Y: args.Float(1)},
+							To:        painter.RelativePoint{X: args.Float(2), Y: args.Float(3)},
+							FromColor: args.Color(4),
+							ToColor:   args.Color(5),
+						})
+					},
+				},
+				{
+					Name:        "radial",
+					Description: "Зафарбовує фон градієнтом, що змінюється від центру cx cy до кола з радіусом radius.",
+					Args: []Arg{
+						Coord("cx", "центр по горизонталі", AxisX),
+						Coord("cy", "центр по вертикалі", AxisY),
+						positiveDelta("radius", "радіус у частках ширини"),
+						{Name: "inner", Type: Color, Description: "колір у центрі"},
+						{Name: "outer", Type: Color, Description: "колір на колі та за його межами"},
+					},
+					Tweak: func(args Args) painter.StateTweaker {
+						return painter.PatternFill(painter.RadialGradient{
+							Center: painter.RelativePoint{X: args.Float(0), Y: args.Float(1)},
+							Radius: args.Float(2),
+							Inner:  args.Color(3),
+							Outer:  args.Color(4),
+						})
+					},
+				},
+			},
+		},
+		{
+			Name:           "pattern",
+			SubcommandKind: "pattern",
+			Subcommands: []Command{
+				{
+					Name:        "checkerboard",
+					Description: "Зафарбовує фон шаховою дошкою з квадратних клітинок.",
+					Args: []Arg{
+						positiveDelta("cell", "розмір клітинки у частках ширини"),
+						{Name: "color1", Type: Color, Description: "колір лівої верхньої клітинки"},
+						{Name: "color2", Type: Color, Description: "колір сусідніх клітинок"},
+					},
+					Tweak: func(args Args) painter.StateTweaker {
+						return painter.PatternFill(painter.Checkerboard{Cell: args.Float(0), A: args.Color(1), B: args.Color(2)})
+					},
+				},
+				{
+					Name:        "stripes",
+					Description: "Зафарбовує фон смугами двох кольорів, що чергуються.",
+					Args: []Arg{
+						positiveDelta("width", "ширина смуги у частках ширини"),
+						{Name: "color1", Type: Color, Description: "колір першої смуги"},
+						{Name: "color2", Type: Color, Description: "колір другої смуги"},
+						{Name: "angle", Type: Float, Optional: true, Default: 0.0,
+							Description: "нахил смуг у градусах за годинниковою стрілкою; 0 - вертикальні смуги"},
+					},
+					Tweak: func(args Args) painter.StateTweaker {
+						return painter.PatternFill(painter.Stripes{
+							Width: args.Float(0),
+							Angle: args.Float(3) * math.Pi / 180,
+							A:     args.Color(1),
+							B:     args.Color(2),
+						})
+					},
+				},
+			},
+		},
+		{
+			Name:        "update",
+			Description: "Відображає поточний стан малюнку у вікні.",
+			Op: func(p *Parser, args Args) (painter.Operation, error) {
+				return painter.UpdateOp, nil
+			},
+		},
+		{
+			Name: "bgrect",
+			Description: fmt.Sprintf("Малює прямокутник на фоні, за замовчуванням чорний. Прямокутники малюються у "+
+				"порядку додавання; mode=replace видаляє всі попередні. На фоні може бути не більше %d прямокутників: "+
+				"після цього команда повертає помилку, доки їх не видалять mode=replace, reset або undo.",
+				painter.MaxBgRects),
+			Args: append(append([]Arg{
+				Coord("x1", "ліва межа", AxisX),
+				Coord("y1", "верхня межа", AxisY),
+				Coord("x2", "права межа", AxisX),
+				Coord("y2", "нижня межа", AxisY),
+				{Name: "color", Type: Color, Optional: true, Description: "колір прямокутника"},
+			}, paintArgs()...), optional(positiveDelta("border", "товщина рамки у частках ширини")),
+				Arg{Name: "border_color", Type: Color, Optional: true, Description: "колір рамки, за замовчуванням білий"},
+				Arg{Name: "mode", Type: String, Choices: []string{"add", "replace"}, Optional: true, Default: "add",
+					Description: "додати прямокутник до попередніх або замінити їх"},
+			),
+			Tweak: func(args Args) painter.StateTweaker {
+				c, blend := paint(args, 5, optionalColor(args, 4), color.Black)
+				rect := painter.OperationBGRect{
+					Min:         painter.RelativePoint{X: args.Float(0), Y: args.Float(1)},
+					Max:         painter.RelativePoint{X: args.Float(2), Y: args.Float(3)},
+					Color:       c,
+					Blend:       blend,
+					BorderColor: optionalColor(args, 8),
+				}
+				if args[7] != nil {
+					rect.Border = args.Float(7)
+				}
+				if args.String(9) == "replace" {
+					return painter.ReplaceBGRect{Rect: rect}
+				}
+				return rect
+			},
+		},
+		{
+			Name: "figure",
+			Description: fmt.Sprintf("Додає фігуру (жовту літеру \"Т\") з центром у вказаній точці. Фігур може бути не "+
+				"більше %d.", painter.MaxFigures),
+			Args: append([]Arg{
+				Coord("x", "центр по горизонталі", AxisX),
+				Coord("y", "центр по вертикалі", AxisY),
+			}, paintArgs()...),
+			Tweak: func(args Args) painter.StateTweaker {
+				c, blend := paint(args, 2, nil, painter.FigureColor)
+				return painter.OperationFigure{
+					Center: painter.RelativePoint{X: args.Float(0), Y: args.Float(1)},
+					Color:  c,
+					Blend:  blend,
+				}
+			},
+		},
+		{
+			Name:        "move",
+			Description: "Зміщує всі фігури.",
+			Args: []Arg{
+				CoordDelta("dx", "зміщення по горизонталі", AxisX),
+				CoordDelta("dy", "зміщення по вертикалі", AxisY),
+			},
+			Tweak: func(args Args) painter.StateTweaker {
+				return painter.MoveTweaker{Offset: painter.RelativePoint{X: args.Float(0), Y: args.Float(1)}}
+			},
+		},
+		transformCommand("rotate", "Повертає всі фігури на кут у градусах за годинниковою стрілкою навколо їх центрів "+
+			"або навколо точки cx cy. Прямокутники фону не повертаються.", angleArg, rotation),
+		transformCommand("scale", fmt.Sprintf("Масштабує всі фігури відносно їх центрів або точки cx cy. Фігуру не "+
+			"можна збільшити більше ніж у %d разів; прямокутники фону не масштабуються.", painter.MaxScale),
+			factorArg, scaling),
+		{
+			Name:        "reset",
+			Description: "Очищує стан малюнку.",
+			Tweak: func(args Args) painter.StateTweaker {
+				return painter.ResetTweaker{}
+			},
+		},
+		undoCommand(),
+		{
+			Name:           "animate",
+			SubcommandKind: "animation",
+			Subcommands: []Command{
+				{
+					Name:        "move",
+					Description: "Плавно зміщує всі фігури. Анімації виконуються по черзі.",
+					Args: []Arg{
+						CoordDelta("dx", "зміщення по горизонталі", AxisX),
+						CoordDelta("dy", "зміщення по вертикалі", AxisY),
+						{Name: "duration_ms", Type: Int, Positive: true, Description: "тривалість у мілісекундах"},
+						{Name: "easing", Type: String, Choices: easingNames(), Optional: true, Default: "linear",
+							Description: "функція пом'якшення"},
+					},
+					Op: func(p *Parser, args Args) (painter.Operation, error) {
+						if p.Animator == nil {
+							return nil, fmt.Errorf("Animations are not supported")
+						}
+						offset := painter.RelativePoint{X: args.Float(0), Y: args.Float(1)}
+						duration := time.Duration(args.Int(2)) * time.Millisecond
+						return painter.AnimateOp{
+							Animator:  p.Animator,
+							Animation: painter.MoveAnimation(offset, duration, painter.Easings[args.String(3)]),
+						}, nil
+					},
+				},
+				animateTransform("rotate", "Плавно повертає всі фігури.", angleArg, painter.RotateAnimation,
+					func(args Args, idx int) float64 { return args.Float(idx) * math.Pi / 180 }),
+				animateTransform("scale", "Плавно масштабує всі фігури.", factorArg, painter.ScaleAnimation, Args.Float),
+				{
+					Name:        "stop",
+					Description: "Зупиняє поточну анімацію та скасовує заплановані.",
+					Op: func(p *Parser, args Args) (painter.Operation, error) {
+						if p.Animator == nil {
+							return nil, fmt.Errorf("Animations are not supported")
+						}
+						return painter.CancelAnimationsOp{Animator: p.Animator}, nil
+					},
+				},
+			},
+		},
+		{
+			Name:        "help",
+			Description: "Повертає довідку по всіх командах або по одній з них.",
+			Args: []Arg{
+				{Name: "command", Type: String, Optional: true, Description: "назва команди"},
+			},
+			Op: func(p *Parser, args Args) (painter.Operation, error) {
+				if args[0] == nil {
+					return Message(p.registry().Help()), nil
+				}
+				help, ok := p.registry().CommandHelp(args.String(0))
+				if !ok {
+					return nil, fmt.Errorf("Unknown command")
+				}
+				return Message(help), nil
+			},
+		},
+		coordsCommand(),
+		scheduleCommand("after", "Виконує команду один раз через вказаний проміжок часу.", false),
+		scheduleCommand("every", "Виконує команду періодично з вказаним проміжком часу.", true),
+	}
+}
+
+// positiveDelta описує додатний розмір, що задається як зміщення по горизонталі.
+func positiveDelta(name, description string) Arg {
+	arg := CoordDelta(name, description, AxisX)
+	arg.Positive = true
+	return arg
+}
+
+// paintArgs описують необов'язкові непрозорість і режим накладання фігури.
+func paintArgs() []Arg {
+	var modes []string
+	for _, m := range painter.BlendModes {
+		modes = append(modes, string(m))
+	}
+	return []Arg{
+		{Name: "alpha", Type: Float, Bounded: true, Min: 0, Max: 1, Optional: true, Default: 1.0,
+			Description: "непрозорість"},
+		{Name: "blend", Type: String, Choices: modes, Optional: true, Default: string(painter.BlendOver),
+			Description: "режим накладання"},
+	}
+}
+
+// paint повертає колір c з непрозорістю з аргументу idx та режим накладання з аргументу idx+1. Якщо колір не вказаний,
+// прозорість застосовується до кольору за замовчуванням base. Непрозорий колір за замовчуванням і режим over
+// повертаються нульовими значеннями, щоб операція не відрізнялася від створеної без цих аргументів.
+func paint(args Args, idx int, c, base color.Color) (color.Color, painter.BlendMode) {
+	if alpha := args.Float(idx); alpha != 1 {
+		if c == nil {
+			c = base
+		}
+		c = painter.WithAlpha(c, alpha)
+	}
+	blend := painter.BlendMode(args.String(idx + 1))
+	if blend == painter.BlendOver {
+		blend = ""
+	}
+	return c, blend
+}
+
+// optionalColor повертає колір з необов'язкового аргументу або nil, якщо його не вказано.
+func optionalColor(args Args, idx int) color.Color {
+	if args[idx] == nil {
+		return nil
+	}
+	return args.Color(idx)
+}
+
+func optional(arg Arg) Arg {
+	arg.Optional = true
+	return arg
+}
+
+var (
+	angleArg  = Arg{Name: "angle", Type: Float, Description: "кут у градусах"}
+	factorArg = Arg{Name: "factor", Type: Float, Positive: true, Bounded: true, Min: 0, Max: painter.MaxScale,
+		Description: "коефіцієнт масштабування"}
+)
+
+func rotation(angle float64) painter.Matrix { return painter.Rotation(angle * math.Pi / 180) }
+
+func scaling(factor float64) painter.Matrix { return painter.Scaling(factor) }
+
+// pivotArgs описують необов'язкову точку, відносно якої виконується перетворення.
+func pivotArgs() []Arg {
+	return []Arg{
+		optional(Coord("cx", "центр перетворення по горизонталі", AxisX)),
+		optional(Coord("cy", "центр перетворення по вертикалі", AxisY)),
+	}
+}
+
+// pivot повертає точку з аргументів idx та idx+1 або nil, якщо вона не вказана.
+func pivot(args Args, idx int) (*painter.RelativePoint, error) {
+	if args[idx] == nil && args[idx+1] == nil {
+		return nil, nil
+	}
+	if args[idx] == nil || args[idx+1] == nil {
+		return nil, countError{}
+	}
+	return &painter.RelativePoint{X: args.Float(idx), Y: args.Float(idx + 1)}, nil
+}
+
+// transformCommand описує команду, що застосовує до фігур перетворення, задане значенням arg.
+func transformCommand(name, description string, arg Arg, matrix func(float64) painter.Matrix) Command {
+	return Command{
+		Name:        name,
+		Description: description,
+		Args:        append([]Arg{arg}, pivotArgs()...),
+		Op: func(p *Parser, args Args) (painter.Operation, error) {
+			center, err := pivot(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			m := matrix(args.Float(0))
+			// TransformTweaker пропускає фігури, які не можна було б намалювати, тому про таке перетворення
+			// повідомляємо явно.
+			for _, figure := range p.state.FigureOperations {
+				if fm := m.Mul(figure.Matrix()); fm.Degenerate() {
+					return nil, fmt.Errorf("Transform makes a figure degenerate")
+				} else if !fm.Drawable() {
+					return nil, fmt.Errorf("Transform makes a figure more than %d times larger", painter.MaxScale)
+				}
+			}
+			p.update(painter.TransformTweaker{Matrix: m, Pivot: center})
+			return nil, nil
+		},
+	}
+}
+
+// animateTransform описує підкоманду animate, що плавно застосовує до фігур перетворення, задане значенням arg.
+func animateTransform(name, description string, arg Arg,
+	animation func(float64, *painter.RelativePoint, time.Duration, painter.Easing) painter.Animation,
+	value func(args Args, idx int) float64) Command {
+	args := []Arg{
+		arg,
+		{Name: "duration_ms", Type: Int, Positive: true, Description: "тривалість у мілісекундах"},
+		{Name: "easing", Type: String, Choices: easingNames(), Optional: true, Default: "linear",
+			Description: "функція пом'якшення"},
+	}
+	return Command{
+		Name:        name,
+		Description: description + " Анімації виконуються по черзі.",
+		Args:        append(args, pivotArgs()...),
+		Op: func(p *Parser, args Args) (painter.Operation, error) {
+			if p.Animator == nil {
+				return nil, fmt.Errorf("Animations are not supported")
+			}
+			center, err := pivot(args, 3)
+			if err != nil {
+				return nil, err
+			}
+			duration := time.Duration(args.Int(1)) * time.Millisecond
+			return painter.AnimateOp{
+				Animator:  p.Animator,
+				Animation: animation(value(args, 0), center, duration, painter.Easings[args.String(2)]),
+			}, nil
+		},
+	}
+}
+
+func scheduleCommand(name, description string, repeat bool) Command {
+	return Command{
+		Name:        name,
+		Description: description,
+		Args: []Arg{
+			{Name: "interval", Type: Duration, Positive: true, Description: "проміжок часу, наприклад 500ms або 5s"},
+			{Name: "command", Type: Line, Description: "команда скрипту"},
+		},
+		Op: func(p *Parser, args Args) (painter.Operation, error) {
+			if p.Scheduler == nil {
+				return nil, fmt.Errorf("Scheduling is not supported")
+			}
+
+			// Перевіряємо заплановану команду на окремому парсері, щоб не змінити поточний стан.
+			cmd := args.String(1)
+			check := Parser{Animator: p.Animator, Scheduler: p.Scheduler, Registry: p.Registry, vars: p.copyVars(),
+				session: p.session, macros: p.copyMacros(), coords: p.coords, steps: p.steps}
+			_, err := check.run(cmd)
+			// Кроки перевірки враховуються у ліміті поточного скрипту.
+			p.steps = check.steps
+			if err != nil {
+				// Позиція у вкладеній команді не відповідає позиції у скрипті, тому залишаємо лише текст помилки.
+				var parseErr *ParseError
+				if errors.As(err, &parseErr) {
+					err = parseErr.Err
+				}
+				return nil, err
+			}
+			return ScheduleOp{Scheduler: p.Scheduler, Session: p.session, Coords: p.coords, Command: cmd,
+				Delay: args.Duration(0), Repeat: repeat, macros: p.copyMacros()}, nil
+		},
+	}
+}
+
+func easingNames() []string {
+	var names []string
+	for name := range painter.Easings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,143 @@
+package lang
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// Axis позначає, до якої осі належить координатний аргумент.
+type Axis int
+
+const (
+	NoAxis Axis = iota // аргумент не є координатою
+	AxisX
+	AxisY
+)
+
+// CoordSystem задає, як координати з аргументів команд перетворюються у частки текстури, з якими працюють операції
+// painter. Значення v у системі координат відповідає частці (v - Origin) / Scale.
+type CoordSystem struct {
+	// Mode - назва системи: "relative", "pixel" або "viewport".
+	Mode   string
+	Origin painter.RelativePoint
+	Scale  painter.RelativePoint
+}
+
+// RelativeCoords - система координат за замовчуванням, у якій координати задаються частками текстури.
+var RelativeCoords = CoordSystem{Mode: "relative", Scale: painter.RelativePoint{X: 1, Y: 1}}
+
+// PixelCoords повертає систему координат, у якій координати задаються у пікселях текстури.
+func PixelCoords() CoordSystem {
+	size := painter.TextureSize()
+	return CoordSystem{Mode: "pixel", Scale: painter.RelativePoint{X: float64(size.X), Y: float64(size.Y)}}
+}
+
+// ViewportCoords повертає систему координат, у якій точка (x1, y1) відповідає лівому верхньому куту текстури, а
+// (x2, y2) - правому нижньому. Наприклад, viewport -1 1 1 -1 задає математичну систему з центром посередині та віссю
+// Y, спрямованою вгору.
+func ViewportCoords(x1, y1, x2, y2 float64) CoordSystem {
+	return CoordSystem{
+		Mode:   "viewport",
+		Origin: painter.RelativePoint{X: x1, Y: y1},
+		Scale:  painter.RelativePoint{X: x2 - x1, Y: y2 - y1},
+	}
+}
+
+func (cs CoordSystem) axis(axis Axis) (origin, scale float64) {
+	if axis == AxisY {
+		return cs.Origin.Y, cs.Scale.Y
+	}
+	return cs.Origin.X, cs.Scale.X
+}
+
+// bound задає координатному аргументу проміжок допустимих значень у цій системі координат. Для відносних координат
+// зберігається проміжок [-1,1]. В інших системах дозволена область навколо текстури розміром ще по одній текстурі з
+// кожного боку, а зміщення - на три розміри текстури.
+func (cs CoordSystem) bound(arg Arg) Arg {
+	if cs.Mode == RelativeCoords.Mode {
+		return arg
+	}
+	origin, scale := cs.axis(arg.Axis)
+	if arg.Delta {
+		arg.Min, arg.Max = -3*math.Abs(scale), 3*math.Abs(scale)
+	} else {
+		arg.Min, arg.Max = origin-scale, origin+2*scale
+		if arg.Min > arg.Max {
+			arg.Min, arg.Max = arg.Max, arg.Min
+		}
+	}
+	arg.Bounded = true
+	return arg
+}
+
+// toRelative перетворює значення координатного аргументу у частку текстури.
+func (cs CoordSystem) toRelative(v float64, arg Arg) float64 {
+	origin, scale := cs.axis(arg.Axis)
+	if arg.Delta {
+		return v / scale
+	}
+	return (v - origin) / scale
+}
+
+func (cs CoordSystem) String() string {
+	if cs.Mode != "viewport" {
+		return cs.Mode
+	}
+	return fmt.Sprintf("viewport %g %g %g %g",
+		cs.Origin.X, cs.Origin.Y, cs.Origin.X+cs.Scale.X, cs.Origin.Y+cs.Scale.Y)
+}
+
+func coordsCommand() Command {
+	return Command{
+		Name: "coords",
+		Description: "Задає систему координат для наступних команд: relative - частки текстури, pixel - пікселі, " +
+			"viewport - прямокутник x1 y1 x2 y2, що відповідає текстурі. Без аргументів повертає поточну систему.",
+		Args: []Arg{
+			{Name: "mode", Type: String, Choices: []string{"relative", "pixel", "viewport"}, Optional: true,
+				Description: "система координат"},
+			{Name: "x1", Type: Float, Optional: true, Description: "координата лівого краю текстури"},
+			{Name: "y1", Type: Float, Optional: true, Description: "координата верхнього краю текстури"},
+			{Name: "x2", Type: Float, Optional: true, Description: "координата правого краю текстури"},
+			{Name: "y2", Type: Float, Optional: true, Description: "координата нижнього краю текстури"},
+		},
+		Op: func(p *Parser, args Args) (painter.Operation, error) {
+			if args[0] == nil {
+				return Message(p.coordSystem().String() + "\n"), nil
+			}
+
+			viewport := args[1] != nil && args[2] != nil && args[3] != nil && args[4] != nil
+			mode := args.String(0)
+			if (mode == "viewport") != viewport || (mode != "viewport" && args[1] != nil) {
+				return nil, countError{}
+			}
+			switch mode {
+			case "relative":
+				p.coords = RelativeCoords
+			case "pixel":
+				p.coords = PixelCoords()
+			default:
+				if args.Float(1) == args.Float(3) || args.Float(2) == args.Float(4) {
+					return nil, fmt.Errorf("Viewport has zero size")
+				}
+				p.coords = ViewportCoords(args.Float(1), args.Float(2), args.Float(3), args.Float(4))
+			}
+			return nil, nil
+		},
+	}
+}
+
+func (p *Parser) coordSystem() CoordSystem {
+	if p.coords.Mode == "" {
+		return RelativeCoords
+	}
+	return p.coords
+}
+
+// Coords повертає поточну систему координат.
+func (p *Parser) Coords() CoordSystem {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.coordSystem()
+}
@@ -0,0 +1,46 @@
+package lang
+
+import "github.com/MytsV/architecture-lab-3/painter"
+
+// Editor перетворює дії користувача у вікні на зміни стану Parser і одразу показує новий стан. Зміни потрапляють в
+// історію Parser, тому їх можна скасувати так само, як зміни зі скриптів: командою "undo" або методом Undo.
+type Editor struct {
+	Loop   *painter.Loop
+	Parser *Parser
+}
+
+// PlaceFigure додає фігуру з центром у точці at.
+func (e Editor) PlaceFigure(at painter.RelativePoint) error {
+	return e.show(e.Parser.Edit(painter.OperationFigure{Center: at}, true))
+}
+
+// FigureAt повертає номер фігури під точкою at або -1.
+func (e Editor) FigureAt(at painter.RelativePoint) int {
+	return e.Parser.State().FigureAt(at, painter.TextureSize())
+}
+
+// MoveFigure зміщує фігуру з номером idx. Перетягування складається з багатьох зміщень, тому стан для "undo"
+// зберігається, лише якщо record встановлений, - на першому з них.
+func (e Editor) MoveFigure(idx int, offset painter.RelativePoint, record bool) error {
+	return e.show(e.Parser.Edit(painter.MoveFigureTweaker{Index: idx, Offset: offset}, record))
+}
+
+// Reset очищує малюнок.
+func (e Editor) Reset() error {
+	return e.show(e.Parser.Edit(painter.ResetTweaker{}, true))
+}
+
+// Undo скасовує останню зміну.
+func (e Editor) Undo() error {
+	return e.show(e.Parser.Undo())
+}
+
+func (e Editor) show(op painter.Operation, err error) error {
+	if err != nil {
+		return err
+	}
+	if err := e.Loop.Post(op); err != nil {
+		return err
+	}
+	return e.Loop.Post(painter.UpdateOp)
+}
@@ -0,0 +1,91 @@
+package lang
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// eventBuffer - кількість подій, які може накопичити один підписник. Події для повільного клієнта
+// відкидаються, щоб він не блокував вікно.
+const eventBuffer = 64
+
+// Events розсилає події вікна (кліки, клавіші, зміну розміру, закриття) підписникам, наприклад клієнтам
+// EventsHandler. Нульове значення готове до використання.
+type Events struct {
+	mu          sync.Mutex
+	subscribers map[chan []byte]struct{}
+}
+
+// Publish кодує подію у JSON і передає її всім підписникам. Виклик не блокується.
+func (ev *Events) Publish(e any) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("Failed to encode event: %s", err)
+		return
+	}
+
+	ev.mu.Lock()
+	defer ev.mu.Unlock()
+	for ch := range ev.subscribers {
+		select {
+		case ch <- data:
+		default:
+		}
+	}
+}
+
+// Subscribe повертає канал з подіями у форматі JSON та функцію, яка скасовує підписку.
+func (ev *Events) Subscribe() (<-chan []byte, func()) {
+	ch := make(chan []byte, eventBuffer)
+	ev.mu.Lock()
+	if ev.subscribers == nil {
+		ev.subscribers = make(map[chan []byte]struct{})
+	}
+	ev.subscribers[ch] = struct{}{}
+	ev.mu.Unlock()
+
+	return ch, func() {
+		ev.mu.Lock()
+		delete(ev.subscribers, ch)
+		ev.mu.Unlock()
+	}
+}
+
+// EventsHandler конструює обробник HTTP запитів, який транслює події вікна у форматі Server-Sent Events: кожна
+// подія надсилається як рядок "data: <json>" до закриття з'єднання клієнтом.
+func EventsHandler(ev *Events) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		flusher, ok := rw.(http.Flusher)
+		if !ok {
+			http.Error(rw, "Streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		events, cancel := ev.Subscribe()
+		defer cancel()
+
+		rw.Header().Set("Content-Type", "text/event-stream")
+		rw.Header().Set("Cache-Control", "no-cache")
+		rw.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case data := <-events:
+				if _, err := fmt.Fprintf(rw, "data: %s\n\n", data); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Вирази у числових аргументах підтримують числа, змінні ($name), дужки, унарний мінус та операції + - * / %,
+// наприклад ($x + 0.1) або $i*0.05-1.
+
+// errInvalidExpression означає синтаксичну помилку у виразі. Parser повідомляє про неї як про неправильний аргумент.
+var errInvalidExpression = errors.New("invalid expression")
+
+// isExpression перевіряє, чи потрібно обчислювати значення слова як вираз, а не читати його як число.
+func isExpression(s string) bool {
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return false
+	}
+	return strings.ContainsAny(s, "$()+-*/%")
+}
+
+// evalExpr обчислює вираз, беручи значення змінних з vars.
+func evalExpr(s string, vars map[string]float64) (float64, error) {
+	e := exprParser{src: s, vars: vars}
+	value, err := e.sum()
+	if err != nil {
+		return 0, err
+	}
+	if e.skipSpace(); e.pos < len(e.src) {
+		return 0, errInvalidExpression
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, errInvalidExpression
+	}
+	return value, nil
+}
+
+type exprParser struct {
+	src  string
+	pos  int
+	vars map[string]float64
+}
+
+func (e *exprParser) skipSpace() {
+	for e.pos < len(e.src) && unicode.IsSpace(rune(e.src[e.pos])) {
+		e.pos++
+	}
+}
+
+// accept пропускає символ op, якщо він наступний.
+func (e *exprParser) accept(op byte) bool {
+	e.skipSpace()
+	if e.pos < len(e.src) && e.src[e.pos] == op {
+		e.pos++
+		return true
+	}
+	return false
+}
+
+// sum = product {("+" | "-") product}
+func (e *exprParser) sum() (float64, error) {
+	res, err := e.product()
+	for err == nil {
+		var rhs float64
+		switch {
+		case e.accept('+'):
+			rhs, err = e.product()
+			res += rhs
+		case e.accept('-'):
+			rhs, err = e.product()
+			res -= rhs
+		default:
+			return res, nil
+		}
+	}
+	return 0, err
+}
+
+// product = unary {("*" | "/" | "%") unary}
+func (e *exprParser) product() (float64, error) {
+	res, err := e.unary()
+	for err == nil {
+		var rhs float64
+		switch {
+		case e.accept('*'):
+			rhs, err = e.unary()
+			res *= rhs
+		case e.accept('/'):
+			if rhs, err = e.unary(); err == nil && rhs == 0 {
+				err = fmt.Errorf("Division by zero")
+			}
+			res /= rhs
+		case e.accept('%'):
+			if rhs, err = e.unary(); err == nil && rhs == 0 {
+				err = fmt.Errorf("Division by zero")
+			}
+			res = math.Mod(res, rhs)
+		default:
+			return res, nil
+		}
+	}
+	return 0, err
+}
+
+// unary = "-" unary | "+" unary | primary
+func (e *exprParser) unary() (float64, error) {
+	switch {
+	case e.accept('-'):
+		v, err := e.unary()
+		return -v, err
+	case e.accept('+'):
+		return e.unary()
+	}
+	return e.primary()
+}
+
+// primary = number | "$" name | "(" sum ")"
+func (e *exprParser) primary() (float64, error) {
+	switch {
+	case e.accept('('):
+		v, err := e.sum()
+		if err != nil {
+			return 0, err
+		}
+		if !e.accept(')') {
+			return 0, errInvalidExpression
+		}
+		return v, nil
+	case e.accept('$'):
+		start := e.pos
+		for e.pos < len(e.src) && isNameChar(e.src[e.pos], e.pos == start) {
+			e.pos++
+		}
+		name := e.src[start:e.pos]
+		if name == "" {
+			return 0, errInvalidExpression
+		}
+		v, ok := e.vars[name]
+		if !ok {
+			return 0, fmt.Errorf("Undefined variable %s", name)
+		}
+		return v, nil
+	}
+
+	start := e.pos
+	for e.pos < len(e.src) {
+		c := e.src[e.pos]
+		// Знак після "e" належить показнику степеня, наприклад 1e-3.
+		exponentSign := (c == '-' || c == '+') && e.pos > start && (e.src[e.pos-1] == 'e' || e.src[e.pos-1] == 'E')
+		if !(c >= '0' && c <= '9' || c == '.' || c == 'e' || c == 'E' || exponentSign) {
+			break
+		}
+		e.pos++
+	}
+	v, err := strconv.ParseFloat(e.src[start:e.pos], 64)
+	if err != nil {
+		return 0, errInvalidExpression
+	}
+	return v, nil
+}
+
+func isNameChar(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
+
+// expandVars підставляє у текст команди поточні значення змінних ($name). Використовується для команд, які
+// виконуються пізніше, щоб вони бачили значення на момент планування, наприклад змінну циклу. Невідомі змінні
+// залишаються без змін.
+func expandVars(s string, vars map[string]float64) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	var sb strings.Builder
+	for idx := 0; idx < len(s); {
+		if s[idx] != '$' {
+			r, size := utf8.DecodeRuneInString(s[idx:])
+			sb.WriteRune(r)
+			idx += size
+			continue
+		}
+		end := idx + 1
+		for end < len(s) && isNameChar(s[end], end == idx+1) {
+			end++
+		}
+		if v, ok := vars[s[idx+1:end]]; ok {
+			sb.WriteString(formatNumber(v))
+		} else {
+			sb.WriteString(s[idx:end])
+		}
+		idx = end
+	}
+	return sb.String()
+}
+
+func formatNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'g', -1, 64)
+	// Від'ємне число береться в дужки, щоб "$a-$b" не перетворилося на "1--2".
+	if v < 0 {
+		return "(" + s + ")"
+	}
+	return s
+}
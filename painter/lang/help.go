@@ -0,0 +1,104 @@
+package lang
+
+import (
+	"strings"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"golang.org/x/exp/shiny/screen"
+)
+
+// ArgInfo описує аргумент команди для довідки.
+type ArgInfo struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Min         *float64 `json:"min,omitempty"`
+	Max         *float64 `json:"max,omitempty"`
+	Positive    bool     `json:"positive,omitempty"`
+	Choices     []string `json:"choices,omitempty"`
+	Optional    bool     `json:"optional,omitempty"`
+	Default     any      `json:"default,omitempty"`
+}
+
+// CommandInfo описує команду для довідки.
+type CommandInfo struct {
+	Name        string        `json:"name"`
+	Usage       string        `json:"usage,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Args        []ArgInfo     `json:"args,omitempty"`
+	Subcommands []CommandInfo `json:"subcommands,omitempty"`
+}
+
+// Info повертає опис команди для довідки.
+func (c Command) Info() CommandInfo {
+	info := CommandInfo{Name: c.Name, Description: c.Description}
+	if len(c.Subcommands) > 0 {
+		for _, sub := range c.Subcommands {
+			info.Subcommands = append(info.Subcommands, sub.Info())
+		}
+		return info
+	}
+
+	info.Usage = c.Usage()
+	for _, arg := range c.Args {
+		argInfo := ArgInfo{
+			Name:        arg.Name,
+			Type:        arg.Type.String(),
+			Description: arg.Description,
+			Positive:    arg.Positive,
+			Choices:     arg.Choices,
+			Optional:    arg.Optional,
+			Default:     arg.Default,
+		}
+		if arg.Bounded {
+			min, max := arg.Min, arg.Max
+			argInfo.Min, argInfo.Max = &min, &max
+		}
+		info.Args = append(info.Args, argInfo)
+	}
+	return info
+}
+
+// Describe повертає опис усіх команд реєстру у порядку реєстрації.
+func (r *Registry) Describe() []CommandInfo {
+	var res []CommandInfo
+	for _, c := range r.Commands() {
+		res = append(res, c.Info())
+	}
+	return res
+}
+
+// CommandHelp повертає довідку по одній команді.
+func (r *Registry) CommandHelp(name string) (string, bool) {
+	c, ok := r.Lookup(name)
+	if !ok {
+		return "", false
+	}
+	var sb strings.Builder
+	writeHelp(&sb, c, "")
+	return sb.String(), true
+}
+
+// Message містить текст, який потрібно повернути клієнту замість малювання, наприклад результат команди "help".
+// Обробники запитів не надсилають такі операції у painter.Loop.
+type Message string
+
+func (m Message) Do(t screen.Texture) bool {
+	return false
+}
+
+// splitMessages відокремлює повідомлення для клієнта від операцій, які треба надіслати у цикл подій.
+func splitMessages(ops []painter.Operation) ([]painter.Operation, []string) {
+	var (
+		res  []painter.Operation
+		msgs []string
+	)
+	for _, op := range ops {
+		if msg, ok := op.(Message); ok {
+			msgs = append(msgs, string(msg))
+		} else {
+			res = append(res, op)
+		}
+	}
+	return res, msgs
+}
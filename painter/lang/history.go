@@ -0,0 +1,96 @@
+package lang
+
+import (
+	"fmt"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// maxHistory обмежує кількість станів, до яких можна повернутися командою "undo".
+const maxHistory = 64
+
+// update змінює стан малюнку і позначає, що скрипт, який зараз виконується, його змінив.
+func (p *Parser) update(t painter.StateTweaker) {
+	p.state.Update(t)
+	p.changed, p.dirty = true, true
+}
+
+// flush додає до операцій скрипту копію стану, якщо він змінився після попередньої копії. Викликається перед кожною
+// іншою операцією та в кінці скрипту, тож цикл подій отримує стан до "update", а скрипт копіює стан лише тоді, коли
+// він потрібен.
+func (p *Parser) flush(res *[]painter.Operation) {
+	if p.dirty {
+		*res = append(*res, p.snapshot())
+		p.dirty = false
+	}
+}
+
+// commit завершує успішне виконання скрипту: якщо він змінив стан, попередній стан saved зберігається в історії.
+func (p *Parser) commit(saved painter.StatefulOperationList) {
+	if p.changed {
+		p.remember(saved)
+	}
+	p.changed = false
+}
+
+func (p *Parser) remember(st painter.StatefulOperationList) {
+	p.history = append(p.history, st)
+	if extra := len(p.history) - maxHistory; extra > 0 {
+		p.history = append([]painter.StatefulOperationList(nil), p.history[extra:]...)
+	}
+}
+
+// undo повертає останній збережений стан. Повертає false, якщо історія порожня.
+func (p *Parser) undo() bool {
+	if len(p.history) == 0 {
+		return false
+	}
+	p.state = p.history[len(p.history)-1]
+	p.history = p.history[:len(p.history)-1]
+	p.changed = false
+	return true
+}
+
+// Edit застосовує зміну стану так само, як команда скрипту: якщо record встановлений, попередній стан зберігається
+// для команди "undo". Повертає операцію з копією нового стану або помилку, якщо зміна перевищує обмеження на
+// кількість фігур чи прямокутників (painter.MaxFigures, painter.MaxBgRects). Використовується для змін, зроблених
+// у вікні чи через gRPC; record можна не встановлювати, коли кілька змін (наприклад, перетягування фігури) мають
+// скасовуватися разом.
+func (p *Parser) Edit(t painter.StateTweaker, record bool) (painter.Operation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.checkLimits(t); err != nil {
+		return nil, err
+	}
+	if record {
+		p.remember(p.snapshot())
+	}
+	p.state.Update(t)
+	return p.snapshot(), nil
+}
+
+// Undo повертає стан, який був до останньої зміни зі скрипту чи Edit, і операцію, що його малює.
+func (p *Parser) Undo() (painter.Operation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.undo() {
+		return nil, errNothingToUndo
+	}
+	return p.snapshot(), nil
+}
+
+var errNothingToUndo = fmt.Errorf("Nothing to undo")
+
+func undoCommand() Command {
+	return Command{
+		Name:        "undo",
+		Description: "Повертає стан малюнку, який був до останнього скрипту, що його змінив.",
+		Op: func(p *Parser, args Args) (painter.Operation, error) {
+			if !p.undo() {
+				return nil, errNothingToUndo
+			}
+			p.dirty = true
+			return nil, nil
+		},
+	}
+}
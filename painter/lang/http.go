@@ -1,32 +1,206 @@
 package lang
 
 import (
+	"encoding/json"
+	"errors"
 	"io"
 	"log"
+	"mime"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/MytsV/architecture-lab-3/painter"
 )
 
 // HttpHandler конструює обробник HTTP запитів, який дані з запиту віддає у Parser, а потім відправляє отриманий список
-// операцій у painter.Loop.
+// операцій у painter.Loop. Запити з Content-Type application/json розбираються як масив JSON команд (див.
+// Parser.ParseJSON); у разі помилки у відповідь на них надсилається об'єкт {"error": "...", "command": <номер>}. У
+// відповідь на текстовий скрипт з помилкою надсилається об'єкт {"error": "...", "line": <рядок>, "column": <символ>}.
+// Скрипт виконується у сесії з заголовка SessionHeader.
 func HttpHandler(loop *painter.Loop, p *Parser) http.Handler {
 	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		session := requestSession(p, r)
 		var in io.Reader = r.Body
 		if r.Method == http.MethodGet {
 			in = strings.NewReader(r.URL.Query().Get("cmd"))
 		}
 
-		cmds, err := p.Parse(in)
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		isJSON := r.Method != http.MethodGet && mediaType == "application/json"
+
+		var (
+			cmds []painter.Operation
+			err  error
+		)
+		if isJSON {
+			cmds, err = session.ParseJSON(in)
+		} else {
+			cmds, err = session.Parse(in)
+		}
 		if err != nil {
 			log.Printf("Bad script: %s", err)
-			rw.WriteHeader(http.StatusBadRequest)
+			writeJSONError(rw, err)
 			return
 		}
+		cmds, msgs := splitMessages(cmds)
 		for _, cmd := range cmds {
 			loop.Post(cmd)
 		}
-		rw.WriteHeader(http.StatusOK)
+
+		// Повідомлення для клієнта (наприклад, результат "help") повертаються у тілі відповіді.
+		if len(msgs) == 0 {
+			rw.WriteHeader(http.StatusOK)
+		} else if isJSON {
+			rw.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(rw).Encode(map[string][]string{"output": msgs})
+		} else {
+			rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			_, _ = io.WriteString(rw, strings.Join(msgs, ""))
+		}
+	})
+}
+
+// CommandsHandler конструює обробник HTTP запитів до довідки по командам реєстру:
+//   - GET /commands повертає опис усіх команд у форматі JSON;
+//   - GET /commands/{name} повертає опис однієї команди.
+func CommandsHandler(r *Registry) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var body any = r.Describe()
+		if name := strings.Trim(strings.TrimPrefix(req.URL.Path, "/commands"), "/"); name != "" {
+			c, ok := r.Lookup(name)
+			if !ok {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			body = c.Info()
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(body); err != nil {
+			log.Printf("Failed to write commands: %s", err)
+		}
+	})
+}
+
+// jsonError описує тіло відповіді на JSON запит з помилкою.
+type jsonError struct {
+	Error string `json:"error"`
+	// Command містить номер команди, яка не пройшла перевірку, якщо помилка стосується окремої команди.
+	Command *int `json:"command,omitempty"`
+	// Line та Column містять позицію помилки у текстовому скрипті.
+	Line   *int `json:"line,omitempty"`
+	Column *int `json:"column,omitempty"`
+}
+
+func writeJSONError(rw http.ResponseWriter, err error) {
+	body := jsonError{Error: err.Error()}
+	var (
+		cmdErr   CommandError
+		parseErr *ParseError
+	)
+	if errors.As(err, &cmdErr) {
+		body.Command = &cmdErr.Index
+	} else if errors.As(err, &parseErr) {
+		body.Line, body.Column = &parseErr.Line, &parseErr.Column
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(rw).Encode(body)
+}
+
+// SVGHandler конструює обробник HTTP запитів, який віддає поточний стан малюнку з Parser у вигляді SVG документа.
+func SVGHandler(p *Parser) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		rw.Header().Set("Content-Type", "image/svg+xml")
+		if err := painter.ExportSVG(rw, p.State()); err != nil {
+			log.Printf("SVG export failed: %s", err)
+		}
+	})
+}
+
+// JobsHandler конструює обробник HTTP запитів до запланованих завдань Scheduler:
+//   - GET /jobs повертає список завдань у форматі JSON;
+//   - DELETE /jobs скасовує всі завдання;
+//   - DELETE /jobs/{id} скасовує завдання з вказаним ідентифікатором.
+func JobsHandler(s *Scheduler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/jobs"), "/")
+
+		switch {
+		case r.Method == http.MethodGet && id == "":
+			rw.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(rw).Encode(s.Jobs()); err != nil {
+				log.Printf("Failed to write jobs: %s", err)
+			}
+		case r.Method == http.MethodDelete && id == "":
+			s.CancelAll()
+			rw.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodDelete:
+			num, err := strconv.Atoi(id)
+			if err != nil {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if !s.Cancel(num) {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+		default:
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// MacrosHandler конструює обробник HTTP запитів до макросів, описаних у скриптах командою "def":
+//   - GET /macros повертає список макросів у форматі JSON;
+//   - GET /macros/{name} повертає один макрос;
+//   - DELETE /macros видаляє всі макроси;
+//   - DELETE /macros/{name} видаляє макрос з вказаною назвою.
+//
+// Запити стосуються лише макросів сесії з заголовка SessionHeader.
+func MacrosHandler(p *Parser) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		session := requestSession(p, r)
+		name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/macros"), "/")
+
+		switch {
+		case r.Method == http.MethodGet:
+			macros := session.Macros()
+			var body any = macros
+			if name != "" {
+				idx := sort.Search(len(macros), func(i int) bool { return macros[i].Name >= name })
+				if idx == len(macros) || macros[idx].Name != name {
+					rw.WriteHeader(http.StatusNotFound)
+					return
+				}
+				body = macros[idx]
+			}
+			rw.Header().Set("Content-Type", "application/json")
+			if err := json.NewEncoder(rw).Encode(body); err != nil {
+				log.Printf("Failed to write macros: %s", err)
+			}
+		case r.Method == http.MethodDelete && name == "":
+			session.DeleteMacros()
+			rw.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodDelete:
+			if !session.DeleteMacro(name) {
+				rw.WriteHeader(http.StatusNotFound)
+				return
+			}
+			rw.WriteHeader(http.StatusOK)
+		default:
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+		}
 	})
 }
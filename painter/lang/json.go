@@ -0,0 +1,167 @@
+package lang
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// ParseJSON читає масив команд у форматі JSON, наприклад [{"op":"figure","x":0.5,"y":0.5}], і повертає ті ж операції,
+// що й Parse для відповідного текстового скрипту. Аргументи можна передати або іменованими полями (назви беруться з
+// опису команди в Registry), або масивом "args" у порядку аргументів текстової команди:
+// {"op":"animate","args":["move",0.1,0.1,500]}.
+func (p *Parser) ParseJSON(in io.Reader) ([]painter.Operation, error) {
+	return p.parseJSON("", in)
+}
+
+func (p *Parser) parseJSON(session string, in io.Reader) ([]painter.Operation, error) {
+	decoder := json.NewDecoder(in)
+	// Зберігаємо числа у вихідному текстовому вигляді, щоб вони перевірялися так само, як у текстовому скрипті.
+	decoder.UseNumber()
+
+	var cmds []map[string]any
+	if err := decoder.Decode(&cmds); err != nil {
+		return nil, fmt.Errorf("Invalid JSON: %s", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enter(session)
+	defer p.leave()
+	p.steps = 0
+
+	var res []painter.Operation
+	saved, savedVars, savedMacros, savedCoords := p.snapshot(), p.copyVars(), p.copyMacros(), p.coords
+	savedHistory := p.history
+	for idx, cmd := range cmds {
+		line, err := p.jsonToLine(cmd)
+		if err == nil {
+			var ops []painter.Operation
+			ops, err = p.run(line)
+			res = append(res, ops...)
+		}
+		if err != nil {
+			p.state, p.vars, p.macros, p.coords = saved, savedVars, savedMacros, savedCoords
+			p.history, p.changed, p.dirty = savedHistory, false, false
+			return nil, CommandError{Index: idx, Err: err}
+		}
+	}
+	p.flush(&res)
+	p.commit(saved)
+	return res, nil
+}
+
+// CommandError вказує, яка з JSON команд не пройшла перевірку.
+type CommandError struct {
+	Index int
+	Err   error
+}
+
+func (e CommandError) Error() string {
+	return fmt.Sprintf("Command %d: %s", e.Index, e.Err)
+}
+
+func (e CommandError) Unwrap() error {
+	return e.Err
+}
+
+// jsonToLine перетворює JSON команду у рядок текстового скрипту.
+func (p *Parser) jsonToLine(cmd map[string]any) (string, error) {
+	op, ok := cmd["op"].(string)
+	if !ok {
+		return "", fmt.Errorf("Missing op")
+	}
+	if op == "" || Quote(op) != op {
+		return "", fmt.Errorf("Invalid op")
+	}
+	fields := []string{op}
+
+	if args, ok := cmd["args"]; ok {
+		if len(cmd) != 2 {
+			return "", fmt.Errorf("Named arguments can't be combined with args")
+		}
+		list, ok := args.([]any)
+		if !ok {
+			return "", fmt.Errorf("Field args is not an array")
+		}
+		for idx, arg := range list {
+			s, err := jsonValue(arg)
+			if err != nil {
+				return "", fmt.Errorf("Invalid argument at pos %d", idx)
+			}
+			fields = append(fields, s)
+		}
+		return strings.Join(fields, " "), nil
+	}
+
+	// Іменовані поля підтримуються лише для команд без підкоманд; для решти дозволене тільки поле "op".
+	var schema []Arg
+	if c, ok := p.registry().Lookup(op); ok && len(c.Subcommands) == 0 {
+		schema = c.Args
+	} else if m, ok := p.macros[op]; ok {
+		schema = m.command().Args
+	}
+	var names []string
+	missing := false
+	for idx, arg := range schema {
+		names = append(names, arg.Name)
+		value, ok := cmd[arg.Name]
+		if !ok {
+			if !arg.Optional {
+				return "", fmt.Errorf("Missing field %s", arg.Name)
+			}
+			missing = true
+			continue
+		}
+		if _, isNum := value.(json.Number); isNum != (arg.Type == Float || arg.Type == Int) {
+			return "", fmt.Errorf("Invalid argument at pos %d", idx)
+		}
+		s, err := jsonValue(value)
+		if err != nil {
+			return "", fmt.Errorf("Invalid argument at pos %d", idx)
+		}
+		if missing {
+			// Після пропущеного необов'язкового аргументу позиції не збігаються, тому аргумент передається за назвою.
+			s = arg.Name + "=" + s
+		}
+		fields = append(fields, s)
+	}
+	if unknown := unknownFields(cmd, names); len(unknown) > 0 {
+		return "", fmt.Errorf("Unknown fields: %s", strings.Join(unknown, ", "))
+	}
+	return strings.Join(fields, " "), nil
+}
+
+func jsonValue(v any) (string, error) {
+	switch v := v.(type) {
+	case json.Number:
+		return v.String(), nil
+	case string:
+		// Рядки беруться в лапки, щоб пропуски, ";" чи "=" не змінили розбір команди.
+		return Quote(v), nil
+	default:
+		return "", fmt.Errorf("unsupported argument type %T", v)
+	}
+}
+
+func unknownFields(cmd map[string]any, known []string) []string {
+	var res []string
+	for name := range cmd {
+		if name == "op" {
+			continue
+		}
+		found := false
+		for _, k := range known {
+			found = found || k == name
+		}
+		if !found {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
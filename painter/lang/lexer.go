@@ -0,0 +1,277 @@
+package lang
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Синтаксис скрипту:
+//   - команди розділяються переведенням рядка або символом ";";
+//   - "#" поза лапками починає коментар до кінця рядка;
+//   - аргументи розділяються пропусками; аргумент у подвійних лапках може містити пропуски, ";" та "#", а також
+//     послідовності \" \\ \n \t;
+//   - аргумент виду name=value задає значення аргументу команди за назвою, наприклад figure y=0.5 x=0.1;
+//   - вираз у дужках може містити пропуски, наприклад figure ($x + 0.1) 0;
+//   - "{" та "}" відкривають і закривають блок команд (див. script.go).
+
+// Token - одне слово команди скрипту.
+type Token struct {
+	// Key містить назву аргументу для іменованих аргументів name=value.
+	Key string
+	// Value містить значення без лапок і з обробленими escape-послідовностями.
+	Value string
+	// Quoted вказує, що значення було записане у лапках.
+	Quoted bool
+	// Line та Column задають позицію початку слова; нумерація з 1.
+	Line, Column int
+
+	// start та end задають межі слова у байтах вихідного рядка.
+	start, end int
+	// brace вказує, що це дужка блоку "{" або "}".
+	brace bool
+}
+
+// ParseError вказує позицію у скрипті, де виникла помилка. Текст помилки не містить позиції, щоб повідомлення
+// залишалися такими ж, як у попередніх версіях.
+type ParseError struct {
+	Line, Column int
+	Err          error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// statement - одна команда скрипту разом з рядком, з якого вона отримана.
+type statement struct {
+	tokens []Token
+	src    string
+}
+
+// rest повертає текст від початку tokens[idx] до кінця команди. Якщо це єдине слово в лапках, повертається його
+// значення.
+func (s statement) rest(idx int) string {
+	tokens := s.tokens[idx:]
+	if len(tokens) == 1 && tokens[0].Quoted && tokens[0].Key == "" {
+		return tokens[0].Value
+	}
+	return s.src[tokens[0].start:tokens[len(tokens)-1].end]
+}
+
+// String повертає текст команди, зібраний зі слів, тому він не містить коментарів та зайвих пробілів.
+func (s statement) String() string {
+	words := make([]string, len(s.tokens))
+	for idx, tok := range s.tokens {
+		words[idx] = tok.Value
+		if tok.Quoted {
+			words[idx] = Quote(tok.Value)
+		}
+		if tok.Key != "" {
+			words[idx] = tok.Key + "=" + words[idx]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Tokenize розбиває скрипт на команди, кожна з яких є списком слів. Порожні команди та коментарі пропускаються.
+func Tokenize(script string) ([][]Token, error) {
+	var res [][]Token
+	for idx, line := range strings.Split(script, "\n") {
+		statements, err := lexLine(line, idx+1)
+		if err != nil {
+			return nil, err
+		}
+		for _, st := range statements {
+			res = append(res, st.tokens)
+		}
+	}
+	return res, nil
+}
+
+// lexLine розбиває один рядок скрипту на команди.
+func lexLine(src string, lineNo int) ([]statement, error) {
+	l := lexer{src: src, line: lineNo}
+	var (
+		res     []statement
+		current []Token
+	)
+	flush := func() {
+		if len(current) > 0 {
+			res = append(res, statement{tokens: current, src: src})
+		}
+		current = nil
+	}
+
+	for {
+		r := l.peek()
+		switch {
+		case r == utf8.RuneError && l.pos >= len(src):
+			flush()
+			return res, nil
+		case r == '#':
+			flush()
+			return res, nil
+		case r == ';':
+			l.next()
+			flush()
+		case r == '{' || r == '}':
+			flush()
+			tok := Token{Value: string(r), Line: lineNo, Column: l.col + 1, start: l.pos, brace: true}
+			l.next()
+			tok.end = l.pos
+			res = append(res, statement{tokens: []Token{tok}, src: src})
+		case unicode.IsSpace(r):
+			l.next()
+		default:
+			tok, err := l.token()
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, tok)
+		}
+	}
+}
+
+type lexer struct {
+	src  string
+	line int
+	pos  int // зміщення у байтах
+	col  int // кількість прочитаних символів
+}
+
+func (l *lexer) peek() rune {
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return r
+}
+
+func (l *lexer) next() rune {
+	r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.pos += size
+	l.col++
+	return r
+}
+
+func (l *lexer) errorf(col int, format string, args ...any) error {
+	return &ParseError{Line: l.line, Column: col, Err: fmt.Errorf(format, args...)}
+}
+
+// token читає одне слово, можливо з назвою аргументу.
+func (l *lexer) token() (Token, error) {
+	tok := Token{Line: l.line, Column: l.col + 1, start: l.pos}
+	value, quoted, err := l.value(true)
+	if err != nil {
+		return Token{}, err
+	}
+	// Слово виду name=value без лапок перед "=" задає іменований аргумент.
+	if !quoted && l.peek() == '=' {
+		l.next()
+		tok.Key = value
+		if value, quoted, err = l.value(false); err != nil {
+			return Token{}, err
+		}
+	}
+	tok.Value, tok.Quoted, tok.end = value, quoted, l.pos
+	return tok, nil
+}
+
+// value читає значення до пропуску, ";", "#" або дужки блоку. Значення у лапках читається до закриваючих лапок, а
+// всередині круглих дужок дозволені пропуски. Якщо stopAtKey, читання зупиняється на "=", перед яким стоїть назва
+// аргументу.
+func (l *lexer) value(stopAtKey bool) (string, bool, error) {
+	if l.peek() == '"' {
+		return l.quoted()
+	}
+	start := l.pos
+	depth := 0
+	for l.pos < len(l.src) {
+		r := l.peek()
+		if depth == 0 && (unicode.IsSpace(r) || r == ';' || r == '#' || r == '{' || r == '}' ||
+			(stopAtKey && r == '=' && isIdentifier(l.src[start:l.pos]))) {
+			break
+		}
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		l.next()
+	}
+	return l.src[start:l.pos], false, nil
+}
+
+func (l *lexer) quoted() (string, bool, error) {
+	startCol := l.col + 1
+	l.next()
+	var sb strings.Builder
+	for l.pos < len(l.src) {
+		r := l.next()
+		switch r {
+		case '"':
+			if end := l.peek(); l.pos < len(l.src) && !unicode.IsSpace(end) && !strings.ContainsRune(";#{}", end) {
+				return "", false, l.errorf(l.col+1, "Unexpected character after string")
+			}
+			return sb.String(), true, nil
+		case '\\':
+			escCol := l.col
+			if l.pos >= len(l.src) {
+				return "", false, l.errorf(escCol, "Unterminated string")
+			}
+			switch e := l.next(); e {
+			case '"', '\\':
+				sb.WriteRune(e)
+			case 'n':
+				sb.WriteRune('\n')
+			case 't':
+				sb.WriteRune('\t')
+			default:
+				return "", false, l.errorf(escCol, "Invalid escape sequence \\%c", e)
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return "", false, l.errorf(startCol, "Unterminated string")
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for idx, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (idx == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Quote повертає аргумент у вигляді, в якому лексер прочитає його як одне слово з тим самим значенням.
+func Quote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\r\n;#\"=\\{}()$") {
+		return s
+	}
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
@@ -0,0 +1,162 @@
+package lang
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// maxCallDepth обмежує вкладеність викликів макросів, щоб рекурсивний макрос не заблокував Parser.
+const maxCallDepth = 32
+
+// Macro - команда, описана у скрипті через "def name(a, b) { ... }". Параметри макросу - числові змінні, доступні в
+// тілі як $a та $b. Макроси зберігаються між запитами, як і змінні, але окремо для кожної сесії клієнта (див. Session).
+type Macro struct {
+	Name   string   `json:"name"`
+	Params []string `json:"params"`
+	Body   string   `json:"body"`
+
+	nodes []node
+}
+
+// command описує аргументи макросу у вигляді команди, щоб перевіряти їх так само, як аргументи вбудованих команд.
+func (m *Macro) command() Command {
+	c := Command{Name: m.Name}
+	for _, param := range m.Params {
+		c.Args = append(c.Args, Arg{Name: param, Type: Float})
+	}
+	return c
+}
+
+// define виконує "def name(a, b) { ... }" або "def name { ... }".
+func (p *Parser) define(n defNode) error {
+	tokens := n.st.tokens
+	if len(tokens) != 2 || tokens[1].Key != "" || tokens[1].Quoted {
+		return errorAt(tokens[0], fmt.Errorf("Expected def name(params)"))
+	}
+	header := tokens[1]
+	name, params, err := parseSignature(header.Value)
+	if err != nil {
+		return errorAt(header, err)
+	}
+	if _, ok := p.registry().Lookup(name); ok || contains(keywords, name) {
+		return errorAt(header, fmt.Errorf("Command %s already exists", name))
+	}
+
+	if p.macros == nil {
+		p.macros = make(map[string]*Macro)
+	}
+	p.macros[name] = &Macro{Name: name, Params: params, Body: n.source, nodes: n.body}
+	return nil
+}
+
+// parseSignature розбирає заголовок макросу виду "name" або "name(a, b)".
+func parseSignature(s string) (string, []string, error) {
+	name, rest, hasParams := strings.Cut(s, "(")
+	if !isIdentifier(name) {
+		return "", nil, fmt.Errorf("Invalid macro name")
+	}
+	if !hasParams {
+		return name, nil, nil
+	}
+	list, ok := strings.CutSuffix(rest, ")")
+	if !ok {
+		return "", nil, fmt.Errorf("Expected ) after parameters")
+	}
+
+	var params []string
+	if strings.TrimSpace(list) == "" {
+		return name, nil, nil
+	}
+	for _, param := range strings.Split(list, ",") {
+		param = strings.TrimSpace(param)
+		if !isIdentifier(param) {
+			return "", nil, fmt.Errorf("Invalid parameter name %q", param)
+		}
+		if contains(params, param) {
+			return "", nil, fmt.Errorf("Duplicate parameter %s", param)
+		}
+		params = append(params, param)
+	}
+	return name, params, nil
+}
+
+func (p *Parser) macro(tok Token) (*Macro, bool) {
+	if tok.Quoted || tok.Key != "" {
+		return nil, false
+	}
+	m, ok := p.macros[tok.Value]
+	return m, ok
+}
+
+// call виконує тіло макросу, присвоюючи параметрам значення аргументів. Після виклику параметри отримують попередні
+// значення.
+func (p *Parser) call(m *Macro, st statement, res *[]painter.Operation) error {
+	name := st.tokens[0]
+	if p.depth >= maxCallDepth {
+		return errorAt(name, fmt.Errorf("Macro calls are nested deeper than %d", maxCallDepth))
+	}
+	args, err := m.command().parseArgs(st, 1, p)
+	if err != nil {
+		return err
+	}
+
+	if p.vars == nil {
+		p.vars = make(map[string]float64)
+	}
+	saved := make(map[string]*float64, len(m.Params))
+	for idx, param := range m.Params {
+		if prev, ok := p.vars[param]; ok {
+			saved[param] = &prev
+		} else {
+			saved[param] = nil
+		}
+		p.vars[param] = args.Float(idx)
+	}
+	defer func() {
+		for param, prev := range saved {
+			if prev != nil {
+				p.vars[param] = *prev
+			} else {
+				delete(p.vars, param)
+			}
+		}
+	}()
+
+	p.depth++
+	defer func() { p.depth-- }()
+	if err := p.exec(m.nodes, res); err != nil {
+		// Позиція у тілі макросу стосується іншого скрипту, тому помилка вказує на місце виклику.
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			err = parseErr.Err
+		}
+		return errorAt(name, fmt.Errorf("In %s: %w", m.Name, err))
+	}
+	return nil
+}
+
+// Macros повертає макроси сесії за замовчуванням, впорядковані за назвою.
+func (p *Parser) Macros() []Macro {
+	return p.Session("").Macros()
+}
+
+// DeleteMacro видаляє макрос сесії за замовчуванням. Повертає false, якщо макросу з такою назвою немає.
+func (p *Parser) DeleteMacro(name string) bool {
+	return p.Session("").DeleteMacro(name)
+}
+
+// DeleteMacros видаляє всі макроси сесії за замовчуванням.
+func (p *Parser) DeleteMacros() {
+	p.Session("").DeleteMacros()
+}
+
+func (p *Parser) copyMacros() map[string]*Macro {
+	macros := make(map[string]*Macro, len(p.macros))
+	for name, m := range p.macros {
+		macros[name] = m
+	}
+	return macros
+}
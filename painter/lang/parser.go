@@ -1,12 +1,10 @@
 package lang
 
 import (
-	"bufio"
 	"fmt"
-	"image/color"
 	"io"
-	"strconv"
-	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/MytsV/architecture-lab-3/painter"
 )
@@ -15,28 +13,143 @@ import (
 type Parser struct {
 	// Зберігає стан малюнку у спеціальній операції.
 	state painter.StatefulOperationList
+	// Захищає стан від одночасного доступу з різних HTTP запитів.
+	mu sync.Mutex
+
+	// Animator програє анімації, описані командою "animate". Якщо не вказаний, анімації не підтримуються.
+	Animator *painter.Animator
+	// Scheduler виконує команди "after" та "every". Якщо не вказаний, планування не підтримується.
+	Scheduler *Scheduler
+	// Registry містить команди, які розпізнає парсер. Якщо не вказаний, використовується DefaultRegistry.
+	Registry *Registry
+
+	// Змінні скрипту, задані командою "let".
+	vars map[string]float64
+	// Макроси, описані командою "def", за ідентифікаторами сесій (див. Session). Під час розбору скрипту macros
+	// містить макроси сесії session, для якої він виконується; depth - поточна глибина викликів макросів.
+	sessions map[string]map[string]*Macro
+	session  string
+	macros   map[string]*Macro
+	depth    int
+	// Кількість кроків, виконаних у поточному виклику Parse або ParseJSON (див. maxSteps).
+	steps int
+	// Система координат, задана командою "coords". Нульове значення означає відносні координати.
+	coords CoordSystem
+
+	// Попередні стани малюнку для команди "undo" та ознака того, що поточний скрипт змінив стан.
+	history []painter.StatefulOperationList
+	changed bool
+	// Ознака того, що стан змінився після останньої копії, доданої до операцій скрипту (див. flush).
+	dirty bool
+
+	// Текст останньої отриманої команди. Зберігається окремо від mu, щоб його можна було прочитати під час
+	// виконання довгого скрипту.
+	last atomic.Value
 }
 
 func (p *Parser) Parse(in io.Reader) ([]painter.Operation, error) {
-	var res []painter.Operation
+	return p.parse("", in)
+}
 
-	scanner := bufio.NewScanner(in)
-	scanner.Split(bufio.ScanLines)
-	for scanner.Scan() {
-		commandLine := scanner.Text()
-		// Отримуємо відповідну до команди структуру.
-		op, err := p.process(commandLine)
+func (p *Parser) parse(session string, in io.Reader) ([]painter.Operation, error) {
+	// Блоки команд можуть займати кілька рядків, тому скрипт читається повністю.
+	src, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
 
-		if err != nil {
-			// Якщо виникла неопізнана помилка при обробці операції, повертаємо цю помилку.
-			return nil, err
-		} else if op != nil {
-			// Додаємо операцію у список до передачі в цикл.
-			res = append(res, op)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enter(session)
+	defer p.leave()
+	return p.script(string(src))
+}
+
+// script виконує скрипт src з поточними макросами та системою координат. Викликається під p.mu.
+func (p *Parser) script(src string) ([]painter.Operation, error) {
+	p.steps = 0
+
+	// Якщо скрипт містить помилку, повертаємо стан, змінні, макроси та систему координат, які були до його розбору.
+	saved, savedVars, savedMacros, savedCoords := p.snapshot(), p.copyVars(), p.copyMacros(), p.coords
+	savedHistory := p.history
+	res, err := p.run(src)
+	if err != nil {
+		p.state, p.vars, p.macros, p.coords = saved, savedVars, savedMacros, savedCoords
+		p.history, p.changed, p.dirty = savedHistory, false, false
+		return nil, err
+	}
+	p.flush(&res)
+	p.commit(saved)
+	return res, nil
+}
+
+// LastCommand повертає текст останньої команди, яку отримав парсер, навіть якщо вона завершилася помилкою.
+func (p *Parser) LastCommand() string {
+	last, _ := p.last.Load().(string)
+	return last
+}
+
+// State повертає копію поточного стану малюнку.
+func (p *Parser) State() painter.StatefulOperationList {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.snapshot()
+}
+
+// Tweak застосовує зміну до стану малюнку і повертає операцію з копією нового стану. На відміну від Edit, зміна не
+// зберігається в історії та не перевіряє обмежень, тому Tweak використовується для проміжних кадрів анімації.
+func (p *Parser) Tweak(t painter.StateTweaker) painter.Operation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.state.Update(t)
+	return p.snapshot()
+}
+
+// checkLimits перевіряє, чи можна застосувати t, не перевищивши обмеження на кількість прямокутників та фігур.
+func (p *Parser) checkLimits(t painter.StateTweaker) error {
+	switch t.(type) {
+	case painter.OperationBGRect:
+		if len(p.state.BgRectOperations) >= painter.MaxBgRects {
+			return fmt.Errorf("Background already has %d rectangles", painter.MaxBgRects)
+		}
+	case painter.OperationFigure:
+		if len(p.state.FigureOperations) >= painter.MaxFigures {
+			return fmt.Errorf("Picture already has %d figures", painter.MaxFigures)
 		}
 	}
+	return nil
+}
 
-	return res, nil
+func (p *Parser) snapshot() painter.StatefulOperationList {
+	st := p.state
+	// Копіюємо зрізи, щоб наступні зміни стану не змінили операцію, яка вже надіслана у цикл подій.
+	st.BgRectOperations = append([]painter.OperationBGRect(nil), p.state.BgRectOperations...)
+	st.FigureOperations = make([]*painter.OperationFigure, len(p.state.FigureOperations))
+	for idx, op := range p.state.FigureOperations {
+		figure := *op
+		st.FigureOperations[idx] = &figure
+	}
+	return st
+}
+
+func (p *Parser) copyVars() map[string]float64 {
+	vars := make(map[string]float64, len(p.vars))
+	for name, v := range p.vars {
+		vars[name] = v
+	}
+	return vars
+}
+
+// Vars повертає копію змінних скрипту.
+func (p *Parser) Vars() map[string]float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.copyVars()
+}
+
+// CommandNames повертає назви всіх команд скрипту з DefaultRegistry.
+func CommandNames() []string {
+	return DefaultRegistry.Names()
 }
 
 type countError struct{}
@@ -45,85 +158,81 @@ func (e countError) Error() string {
 	return "Invalid argument count"
 }
 
-// process обробляє текстову команду, повертаючи співвідносну операцію для додання в чергу. Враховує потребу редагування стану.
-func (p *Parser) process(cmd string) (painter.Operation, error) {
-	var tweaker painter.StateTweaker
+// process обробляє команду, повертаючи співвідносну операцію для додання в чергу. Враховує потребу редагування стану.
+func (p *Parser) process(st statement) (painter.Operation, error) {
+	p.last.Store(st.String())
+	name := st.tokens[0]
+	c, ok := p.registry().Lookup(name.Value)
+	if !ok || name.Key != "" {
+		return nil, errorAt(name, fmt.Errorf("Unknown command"))
+	}
+	return p.apply(c, st, 1)
+}
 
-	// Розділяємо строку на окремі текстові строки команди за пропусками.
-	fields := strings.Fields(cmd)
-	switch fields[0] {
-	case "white":
-		if len(fields) > 1 {
-			return nil, countError{}
-		}
-		tweaker = painter.OperationFill{Color: color.White}
-	case "green":
-		if len(fields) > 1 {
-			return nil, countError{}
+// apply перевіряє аргументи команди, що починаються з st.tokens[first], за її описом і створює операцію.
+func (p *Parser) apply(c Command, st statement, first int) (painter.Operation, error) {
+	if len(c.Subcommands) > 0 {
+		if len(st.tokens) == first {
+			return nil, errorAt(st.tokens[first-1], countError{})
 		}
-		tweaker = painter.OperationFill{Color: color.RGBA{G: 0xff, A: 0xff}}
-	case "update":
-		if len(fields) > 1 {
-			return nil, countError{}
+		name := st.tokens[first]
+		sub, ok := c.subcommand(name.Value)
+		if !ok || name.Key != "" {
+			return nil, errorAt(name, fmt.Errorf("Unknown %s", c.SubcommandKind))
 		}
-		return painter.UpdateOp, nil
-	case "bgrect":
-		args, err := processArguments(fields[1:], 4)
-		if err != nil {
-			return nil, err
-		}
-		tweaker = painter.OperationBGRect{
-			Min: painter.RelativePoint{X: args[0], Y: args[1]},
-			Max: painter.RelativePoint{X: args[2], Y: args[3]},
-		}
-	case "figure":
-		args, err := processArguments(fields[1:], 2)
-		if err != nil {
-			return nil, err
-		}
-		tweaker = painter.OperationFigure{
-			Center: painter.RelativePoint{X: args[0], Y: args[1]},
-		}
-	case "move":
-		args, err := processArguments(fields[1:], 2)
+		return p.apply(*sub, st, first+1)
+	}
+
+	args, err := c.parseArgs(st, first, p)
+	if err != nil {
+		return nil, err
+	}
+	if c.Tweak == nil {
+		op, err := c.Op(p, args)
 		if err != nil {
-			return nil, err
-		}
-		tweaker = painter.MoveTweaker{
-			Offset: painter.RelativePoint{X: args[0], Y: args[1]},
-		}
-	case "reset":
-		if len(fields) > 1 {
-			return nil, countError{}
+			return nil, errorAt(st.tokens[0], err)
 		}
-		tweaker = painter.ResetTweaker{}
-	default:
-		return nil, fmt.Errorf("Unknown command")
+		return op, nil
 	}
 
-	if tweaker != nil {
-		p.state.Update(tweaker)
+	t := c.Tweak(args)
+	if err := p.checkLimits(t); err != nil {
+		return nil, errorAt(st.tokens[0], err)
 	}
-	// Надсилаємо операцію зі станом у цикл подій, якщо більше ніякої не поверталося.
-	return p.state, nil
+	// Операцію зі станом додає flush, щоб команди скрипту не копіювали стан кожна окремо.
+	p.update(t)
+	return nil, nil
 }
 
-func processArguments(args []string, requiredLen int) ([]float64, error) {
-	if len(args) != requiredLen {
-		return nil, countError{}
+// errorAt додає до помилки позицію слова, якщо вона ще не вказана.
+func errorAt(tok Token, err error) error {
+	if _, ok := err.(*ParseError); ok {
+		return err
 	}
-	var processed []float64
-	for idx, arg := range args {
-		num, err := strconv.ParseFloat(arg, 64)
-		if err != nil {
-			return nil, fmt.Errorf("Invalid argument at pos %d", idx)
-		}
-		if num >= -1 && num <= 1 {
-			processed = append(processed, num)
-		} else {
-			return nil, fmt.Errorf("Value at pos %d is not in [-1,1] range", idx)
+	return &ParseError{Line: tok.Line, Column: tok.Column, Err: err}
+}
+
+func (p *Parser) registry() *Registry {
+	if p.Registry != nil {
+		return p.Registry
+	}
+	return DefaultRegistry
+}
+
+// ValidateArguments перевіряє числові аргументи команди за тими ж правилами, що й при розборі скрипту. Дозволяє
+// іншим API будувати операції без форматування тексту команд.
+func ValidateArguments(args ...float64) error {
+	for idx, num := range args {
+		if err := checkValue(num, idx); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return processed, nil
+func checkValue(num float64, pos int) error {
+	if num >= -1 && num <= 1 {
+		return nil
+	}
+	return fmt.Errorf("Value at pos %d is not in [-1,1] range", pos)
 }
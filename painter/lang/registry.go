@@ -0,0 +1,458 @@
+package lang
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// ArgType задає тип аргументу команди.
+type ArgType int
+
+const (
+	Float    ArgType = iota // число з рухомою комою
+	Int                     // ціле число
+	String                  // одне слово без пропусків
+	Duration                // проміжок часу у форматі time.ParseDuration, наприклад "500ms"
+	Line                    // решта рядка; може бути лише останнім аргументом
+	Color                   // колір: назва, "#rrggbb", "#rrggbbaa", rgb(r, g, b) або rgba(r, g, b, a)
+)
+
+func (t ArgType) String() string {
+	switch t {
+	case Float:
+		return "float"
+	case Int:
+		return "int"
+	case String:
+		return "string"
+	case Duration:
+		return "duration"
+	case Line:
+		return "command"
+	case Color:
+		return "color"
+	default:
+		return "unknown"
+	}
+}
+
+// Arg описує аргумент команди.
+type Arg struct {
+	Name        string
+	Type        ArgType
+	Description string
+
+	// Bounded обмежує значення числового аргументу проміжком [Min, Max].
+	Bounded  bool
+	Min, Max float64
+	// Positive вимагає, щоб числовий аргумент або проміжок часу був більшим за нуль.
+	Positive bool
+	// Choices обмежує можливі значення рядкового аргументу.
+	Choices []string
+
+	// Axis позначає координату. Вона задається у системі координат Parser (див. команду "coords") і перетворюється
+	// у частку текстури; проміжок Min, Max тоді залежить від системи координат.
+	Axis Axis
+	// Delta позначає координатне зміщення, яке перетворюється без урахування початку координат.
+	Delta bool
+
+	// Optional аргумент можна пропустити, тоді замість нього використовується Default. Необов'язкові аргументи мають
+	// іти після обов'язкових.
+	Optional bool
+	Default  any
+}
+
+// Coord описує координату точки по осі axis. У відносних координатах вона має бути з проміжку [-1,1].
+func Coord(name, description string, axis Axis) Arg {
+	return Arg{Name: name, Type: Float, Description: description, Bounded: true, Min: -1, Max: 1, Axis: axis}
+}
+
+// CoordDelta описує зміщення по осі axis. У відносних координатах воно має бути з проміжку [-1,1].
+func CoordDelta(name, description string, axis Axis) Arg {
+	arg := Coord(name, description, axis)
+	arg.Delta = true
+	return arg
+}
+
+// parse перетворює текстовий аргумент у значення відповідного типу і перевіряє його. pos використовується у
+// повідомленнях про помилки.
+func (a Arg) parse(s string, pos int) (any, error) {
+	var (
+		value any
+		num   float64
+	)
+	switch a.Type {
+	case Float:
+		f, err := strconv.ParseFloat(s, 64)
+		// ParseFloat розпізнає NaN та Inf. Їх відхиляємо одразу, бо порівняння з NaN завжди хибні і воно пройшло б
+		// перевірку діапазону.
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("Invalid argument at pos %d", pos)
+		}
+		value, num = f, f
+	case Int:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid argument at pos %d", pos)
+		}
+		value, num = i, float64(i)
+	case Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid argument at pos %d", pos)
+		}
+		value, num = d, float64(d)
+	case Color:
+		c, ok := parseColor(s)
+		if !ok {
+			return nil, fmt.Errorf("Invalid color at pos %d", pos)
+		}
+		return c, nil
+	default:
+		if len(a.Choices) > 0 && !contains(a.Choices, s) {
+			return nil, fmt.Errorf("Unknown %s", a.Name)
+		}
+		return s, nil
+	}
+
+	if a.Bounded && (num < a.Min || num > a.Max) {
+		return nil, fmt.Errorf("Value at pos %d is not in [%g,%g] range", pos, a.Min, a.Max)
+	}
+	if a.Positive && num <= 0 {
+		return nil, fmt.Errorf("Value at pos %d is not positive", pos)
+	}
+	return value, nil
+}
+
+// Usage повертає позначення аргументу для довідки, наприклад "x" або "[easing]".
+func (a Arg) Usage() string {
+	name := a.Name
+	if a.Type == Line {
+		name += "..."
+	}
+	if a.Optional {
+		return "[" + name + "]"
+	}
+	return name
+}
+
+// Constraint повертає текстовий опис обмежень аргументу, наприклад "[-1,1]" або "linear|ease-in".
+func (a Arg) Constraint() string {
+	var parts []string
+	if a.Bounded {
+		parts = append(parts, fmt.Sprintf("[%g,%g]", a.Min, a.Max))
+	}
+	if a.Positive {
+		parts = append(parts, "> 0")
+	}
+	if len(a.Choices) > 0 {
+		parts = append(parts, strings.Join(a.Choices, "|"))
+	}
+	if a.Optional && a.Default != nil {
+		parts = append(parts, fmt.Sprintf("default %v", a.Default))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Args містить перевірені значення аргументів команди у порядку їх оголошення.
+type Args []any
+
+func (a Args) Float(i int) float64 { return a[i].(float64) }
+
+func (a Args) Int(i int) int { return a[i].(int) }
+
+func (a Args) String(i int) string { return a[i].(string) }
+
+func (a Args) Duration(i int) time.Duration { return a[i].(time.Duration) }
+
+func (a Args) Color(i int) color.Color { return a[i].(color.Color) }
+
+// Command описує команду скрипту: її назву, аргументи та спосіб створення операції.
+type Command struct {
+	Name        string
+	Description string
+	Args        []Arg
+
+	// Tweak створює зміну стану малюнку. Після її застосування Parser повертає операцію з поточним станом.
+	Tweak func(args Args) painter.StateTweaker
+	// Op створює операцію, яка надсилається у цикл подій без зміни стану. Використовується, якщо Tweak не вказаний.
+	Op func(p *Parser, args Args) (painter.Operation, error)
+
+	// Subcommands задає підкоманди, назва яких іде першим аргументом, наприклад "animate move". Команда з
+	// підкомандами не має власних аргументів і конструкторів.
+	Subcommands []Command
+	// SubcommandKind називає підкоманду у повідомленні про невідому підкоманду, наприклад "animation".
+	SubcommandKind string
+}
+
+// Usage повертає синтаксис команди для довідки, наприклад "figure x y".
+func (c Command) Usage() string {
+	parts := []string{c.Name}
+	for _, arg := range c.Args {
+		parts = append(parts, arg.Usage())
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c Command) validate() error {
+	if c.Name == "" || Quote(c.Name) != c.Name {
+		return fmt.Errorf("invalid command name %q", c.Name)
+	}
+	if contains(keywords, c.Name) {
+		return fmt.Errorf("command name %s is a keyword", c.Name)
+	}
+	if len(c.Subcommands) > 0 {
+		if len(c.Args) > 0 || c.Tweak != nil || c.Op != nil {
+			return fmt.Errorf("command %s: subcommands can't be combined with arguments or constructors", c.Name)
+		}
+		for _, sub := range c.Subcommands {
+			if err := sub.validate(); err != nil {
+				return fmt.Errorf("command %s: %w", c.Name, err)
+			}
+		}
+		return nil
+	}
+	if (c.Tweak == nil) == (c.Op == nil) {
+		return fmt.Errorf("command %s: exactly one of Tweak and Op must be set", c.Name)
+	}
+	optional := false
+	for idx, arg := range c.Args {
+		if arg.Optional {
+			optional = true
+		} else if optional {
+			return fmt.Errorf("command %s: required argument %s follows an optional one", c.Name, arg.Name)
+		}
+		if arg.Type == Line && idx != len(c.Args)-1 {
+			return fmt.Errorf("command %s: argument %s must be the last one", c.Name, arg.Name)
+		}
+	}
+	return nil
+}
+
+func (c Command) subcommand(name string) (*Command, bool) {
+	for idx := range c.Subcommands {
+		if c.Subcommands[idx].Name == name {
+			return &c.Subcommands[idx], true
+		}
+	}
+	return nil, false
+}
+
+// parseArgs перевіряє кількість аргументів, що починаються з st.tokens[first], і перетворює їх у значення відповідних
+// типів. Спочатку йдуть позиційні аргументи, потім іменовані (name=value). Аргумент типу Line забирає решту команди.
+// Числові аргументи можуть бути виразами зі змінними Parser, а координати перетворюються з його системи координат.
+func (c Command) parseArgs(st statement, first int, p *Parser) (Args, error) {
+	vars, coords := p.vars, p.coordSystem()
+	tokens := st.tokens[first:]
+	total := len(c.Args)
+	// Позиція для повідомлення про неправильну кількість аргументів - кінець команди.
+	countErr := errorAt(st.tokens[len(st.tokens)-1], countError{})
+
+	// Спочатку визначаємо, яке слово відповідає якому аргументу, щоб помилки кількості повідомлялися раніше за
+	// помилки значень, як і до появи іменованих аргументів.
+	assigned := make([]int, total)
+	for idx := range assigned {
+		assigned[idx] = -1
+	}
+	positional, named := 0, false
+	line := -1
+	for idx, tok := range tokens {
+		if tok.Key == "" {
+			if named {
+				return nil, errorAt(tok, fmt.Errorf("Positional argument after named ones"))
+			}
+			if positional >= total {
+				return nil, countErr
+			}
+			assigned[positional] = idx
+			if c.Args[positional].Type == Line {
+				line = positional
+				break
+			}
+			positional++
+			continue
+		}
+
+		named = true
+		argIdx := c.argIndex(tok.Key)
+		if argIdx < 0 {
+			return nil, errorAt(tok, fmt.Errorf("Unknown argument %s", tok.Key))
+		}
+		if assigned[argIdx] >= 0 {
+			return nil, errorAt(tok, fmt.Errorf("Duplicate argument %s", tok.Key))
+		}
+		assigned[argIdx] = idx
+	}
+	for idx, arg := range c.Args {
+		if assigned[idx] < 0 && !arg.Optional {
+			return nil, countErr
+		}
+	}
+
+	args := make(Args, total)
+	for idx, arg := range c.Args {
+		tokIdx := assigned[idx]
+		switch {
+		case tokIdx < 0:
+			args[idx] = arg.Default
+		case idx == line:
+			// Команда виконається пізніше, тому змінні підставляються зараз.
+			args[idx] = expandVars(st.rest(first+tokIdx), vars)
+		default:
+			tok := tokens[tokIdx]
+			s := tok.Value
+			if (arg.Type == Float || arg.Type == Int) && !tok.Quoted && isExpression(s) {
+				v, err := evalExpr(s, vars)
+				if err != nil {
+					return nil, errorAt(tok, exprError(err, fmt.Sprintf("Invalid argument at pos %d", idx)))
+				}
+				// Цілі аргументи записуються без експоненти, щоб Atoi розібрав результат так само, як число в скрипті.
+				format := byte('g')
+				if arg.Type == Int {
+					format = 'f'
+				}
+				s = strconv.FormatFloat(v, format, -1, 64)
+			}
+			if arg.Axis != NoAxis {
+				arg = coords.bound(arg)
+			}
+			value, err := arg.parse(s, idx)
+			if err != nil {
+				return nil, errorAt(tok, err)
+			}
+			if arg.Axis != NoAxis {
+				value = coords.toRelative(value.(float64), arg)
+			}
+			args[idx] = value
+		}
+	}
+	return args, nil
+}
+
+func (c Command) argIndex(name string) int {
+	for idx, arg := range c.Args {
+		if arg.Name == name {
+			return idx
+		}
+	}
+	return -1
+}
+
+// Registry містить команди, які розпізнає Parser.
+type Registry struct {
+	mu       sync.RWMutex
+	commands map[string]Command
+	order    []string
+}
+
+// NewRegistry створює порожній реєстр команд.
+func NewRegistry() *Registry {
+	return &Registry{commands: make(map[string]Command)}
+}
+
+// Register додає команду до реєстру. Повертає помилку, якщо команда з такою назвою вже є або її опис некоректний.
+func (r *Registry) Register(c Command) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.commands[c.Name]; ok {
+		return fmt.Errorf("command %s is already registered", c.Name)
+	}
+	r.commands[c.Name] = c
+	r.order = append(r.order, c.Name)
+	return nil
+}
+
+// Lookup шукає команду за назвою.
+func (r *Registry) Lookup(name string) (Command, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.commands[name]
+	return c, ok
+}
+
+// Commands повертає всі команди у порядку реєстрації.
+func (r *Registry) Commands() []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	res := make([]Command, 0, len(r.order))
+	for _, name := range r.order {
+		res = append(res, r.commands[name])
+	}
+	return res
+}
+
+// Names повертає назви всіх команд у порядку реєстрації.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
+
+// Help повертає довідку по всіх командах реєстру.
+func (r *Registry) Help() string {
+	var sb strings.Builder
+	for _, c := range r.Commands() {
+		writeHelp(&sb, c, "")
+	}
+	return sb.String()
+}
+
+func writeHelp(sb *strings.Builder, c Command, prefix string) {
+	if len(c.Subcommands) > 0 {
+		for _, sub := range c.Subcommands {
+			writeHelp(sb, sub, prefix+c.Name+" ")
+		}
+		return
+	}
+	fmt.Fprintf(sb, "%s%s\n", prefix, c.Usage())
+	if c.Description != "" {
+		fmt.Fprintf(sb, "    %s\n", c.Description)
+	}
+	for _, arg := range c.Args {
+		fmt.Fprintf(sb, "    %s (%s", arg.Name, arg.Type)
+		if constraint := arg.Constraint(); constraint != "" {
+			fmt.Fprintf(sb, ", %s", constraint)
+		}
+		sb.WriteString(")")
+		if arg.Description != "" {
+			fmt.Fprintf(sb, ": %s", arg.Description)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// DefaultRegistry містить вбудовані команди і використовується Parser, якщо інший реєстр не вказаний.
+var DefaultRegistry = NewRegistry()
+
+// Register додає команду до DefaultRegistry. Дозволяє іншим пакетам розширювати мову власними командами.
+func Register(c Command) error {
+	return DefaultRegistry.Register(c)
+}
+
+func init() {
+	for _, c := range builtinCommands() {
+		if err := DefaultRegistry.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package lang
+
+import (
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"golang.org/x/exp/shiny/screen"
+)
+
+// Job описує заплановану команду.
+type Job struct {
+	ID       int    `json:"id"`
+	Command  string `json:"command"`
+	Interval string `json:"interval"`
+	Repeat   bool   `json:"repeat"`
+	// Session - сесія, у якій було заплановано команду. Команда виконується з макросами, які були у цій сесії під час
+	// планування, тож завдання продовжує працювати після закриття з'єднання, що його запланувало.
+	Session string `json:"session,omitempty"`
+	// Runs показує, скільки разів команда вже була виконана.
+	Runs int `json:"runs"`
+}
+
+// Scheduler виконує команди через заданий проміжок часу або періодично. Команда розбирається Parser кожного разу при
+// спрацюванні, тож зміни стану (наприклад, "move") накопичуються. Після кожного спрацювання у Loop надсилається
+// painter.UpdateOp.
+type Scheduler struct {
+	Loop   *painter.Loop
+	Parser *Parser
+
+	mu     sync.Mutex
+	jobs   map[int]*scheduledJob
+	nextID int
+}
+
+type scheduledJob struct {
+	Job
+	// coords та macros - система координат і копія макросів сесії на момент планування. Команда виконується з ними,
+	// навіть якщо після планування їх змінили чи видалили. Зміни, зроблені самою командою, не зберігаються.
+	coords CoordSystem
+	macros map[string]*Macro
+	stop   chan struct{}
+}
+
+// Schedule планує виконання команди cmd через проміжок d (або кожні d, якщо repeat) і повертає ідентифікатор завдання.
+// Команда виконується у сесії за замовчуванням.
+func (s *Scheduler) Schedule(cmd string, d time.Duration, repeat bool) int {
+	return s.ScheduleIn("", cmd, d, repeat)
+}
+
+// ScheduleIn працює так само, як Schedule, але виконує команду у сесії з ідентифікатором session. Команда
+// виконується у системі координат і з макросами сесії, які задані у Parser на момент виклику.
+func (s *Scheduler) ScheduleIn(session, cmd string, d time.Duration, repeat bool) int {
+	return s.schedule(session, s.Parser.Coords(), s.Parser.macrosOf(session), cmd, d, repeat)
+}
+
+func (s *Scheduler) schedule(session string, coords CoordSystem, macros map[string]*Macro, cmd string,
+	d time.Duration, repeat bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.jobs == nil {
+		s.jobs = make(map[int]*scheduledJob)
+	}
+	s.nextID++
+	job := &scheduledJob{
+		Job:    Job{ID: s.nextID, Command: cmd, Interval: d.String(), Repeat: repeat, Session: session},
+		coords: coords,
+		macros: macros,
+		stop:   make(chan struct{}),
+	}
+	s.jobs[job.ID] = job
+	go s.run(job, d)
+	return job.ID
+}
+
+// Jobs повертає список запланованих завдань, впорядкований за ідентифікатором.
+func (s *Scheduler) Jobs() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		res = append(res, job.Job)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res
+}
+
+// Cancel скасовує завдання з ідентифікатором id. Повертає false, якщо такого завдання немає.
+func (s *Scheduler) Cancel(id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return false
+	}
+	close(job.stop)
+	delete(s.jobs, id)
+	return true
+}
+
+// CancelAll скасовує всі заплановані завдання.
+func (s *Scheduler) CancelAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, job := range s.jobs {
+		close(job.stop)
+		delete(s.jobs, id)
+	}
+}
+
+func (s *Scheduler) run(job *scheduledJob, d time.Duration) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-job.stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := s.fire(job)
+		if err != nil {
+			log.Printf("Scheduled job %d failed: %s", job.ID, err)
+		}
+		if err != nil || !job.Repeat {
+			s.Cancel(job.ID)
+			return
+		}
+	}
+}
+
+// fire розбирає команду завдання і надсилає отримані операції у цикл подій.
+func (s *Scheduler) fire(job *scheduledJob) error {
+	ops, err := s.Parser.runJob(job)
+	if err != nil {
+		return err
+	}
+	for _, op := range append(ops, painter.UpdateOp) {
+		if err := s.Loop.Post(op); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	job.Runs++
+	s.mu.Unlock()
+	return nil
+}
+
+// ScheduleOp передає команду в Scheduler, коли черга циклу подій доходить до неї.
+type ScheduleOp struct {
+	Scheduler *Scheduler
+	Session   string
+	// Coords - система координат, у якій команду перевірено під час планування. Нульове значення означає відносні
+	// координати.
+	Coords  CoordSystem
+	Command string
+	Delay   time.Duration
+	Repeat  bool
+
+	// macros - копія макросів сесії, з якими команду перевірено. Якщо не вказана, під час виконання операції
+	// копіюються макроси сесії Session.
+	macros map[string]*Macro
+}
+
+func (op ScheduleOp) Do(t screen.Texture) bool {
+	macros := op.macros
+	if macros == nil {
+		macros = op.Scheduler.Parser.macrosOf(op.Session)
+	}
+	op.Scheduler.schedule(op.Session, op.Coords, macros, op.Command, op.Delay, op.Repeat)
+	return false
+}
+
+// runJob виконує команду завдання так само, як Session.Parse, але з системою координат і макросами, збереженими під
+// час планування. Система координат Parser та макроси сесій після виконання не змінюються.
+func (p *Parser) runJob(job *scheduledJob) ([]painter.Operation, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	saved := p.coords
+	defer func() { p.session, p.macros, p.coords = "", nil, saved }()
+	p.session, p.macros, p.coords = job.Session, job.macros, job.coords
+	// Команда "def" у завданні змінює копію, а не макроси, з якими завдання виконуватиметься наступного разу.
+	p.macros = p.copyMacros()
+	return p.script(job.Command)
+}
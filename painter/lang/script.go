@@ -0,0 +1,323 @@
+package lang
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// Окрім команд з Registry, скрипт підтримує ключові слова:
+//
+//	let x = 0.5                   - присвоює змінній значення виразу; змінні зберігаються між запитами
+//	repeat N { ... }              - виконує блок N разів
+//	for i in A..B { ... }         - виконує блок для кожного цілого i від A до B включно
+//	def name(a, b) { ... }        - описує макрос, який викликається як команда: name 0.1 0.2 (див. macro.go)
+//
+// Блок може займати кілька рядків. Значення змінної підставляється у числові аргументи як $x.
+
+// keywords містить назви, які не можна використати для команд Registry.
+var keywords = []string{"let", "repeat", "for", "def"}
+
+// maxIterations обмежує кількість повторень одного циклу, щоб помилка у скрипті не заблокувала Parser.
+const maxIterations = 10000
+
+// maxSteps обмежує загальну кількість команд та повторень циклів за один виклик Parse або ParseJSON. Вкладені цикли
+// та цикли у макросах інакше могли б виконуватися мільйони разів, утримуючи Parser заблокованим для інших клієнтів.
+const maxSteps = 100000
+
+// node - елемент розібраного скрипту: команда, цикл або опис макросу.
+type node interface{}
+
+type commandNode struct {
+	st statement
+}
+
+type repeatNode struct {
+	st   statement // заголовок "repeat N"
+	body []node
+}
+
+type forNode struct {
+	st   statement // заголовок "for i in A..B"
+	body []node
+}
+
+type defNode struct {
+	st     statement // заголовок "def name(a, b)"
+	body   []node
+	source string // текст тіла макросу
+}
+
+// parseScript розбирає текст скрипту у дерево команд.
+func parseScript(src string) ([]node, error) {
+	sp := scriptParser{lines: strings.Split(src, "\n")}
+	for idx, line := range sp.lines {
+		lineStatements, err := lexLine(line, idx+1)
+		if err != nil {
+			return nil, err
+		}
+		sp.statements = append(sp.statements, lineStatements...)
+	}
+	return sp.block(nil)
+}
+
+type scriptParser struct {
+	lines      []string
+	statements []statement
+	pos        int
+}
+
+// block розбирає команди до кінця скрипту або до "}", якщо open вказує на відкриваючу дужку блоку.
+func (sp *scriptParser) block(open *Token) ([]node, error) {
+	var res []node
+	for sp.pos < len(sp.statements) {
+		st := sp.statements[sp.pos]
+		sp.pos++
+
+		first := st.tokens[0]
+		switch {
+		case first.brace && first.Value == "}":
+			if open == nil {
+				return nil, errorAt(first, fmt.Errorf("Unexpected }"))
+			}
+			return res, nil
+		case first.brace:
+			return nil, errorAt(first, fmt.Errorf("Unexpected {"))
+		case isKeyword(first, "repeat") || isKeyword(first, "for") || isKeyword(first, "def"):
+			if sp.pos >= len(sp.statements) || !sp.statements[sp.pos].tokens[0].brace || sp.statements[sp.pos].tokens[0].Value != "{" {
+				last := st.tokens[len(st.tokens)-1]
+				return nil, &ParseError{Line: last.Line, Column: last.Column + len([]rune(last.Value)),
+					Err: fmt.Errorf("Missing { after %s", first.Value)}
+			}
+			brace := sp.statements[sp.pos].tokens[0]
+			sp.pos++
+			body, err := sp.block(&brace)
+			if err != nil {
+				return nil, err
+			}
+			switch first.Value {
+			case "repeat":
+				res = append(res, repeatNode{st: st, body: body})
+			case "for":
+				res = append(res, forNode{st: st, body: body})
+			default:
+				closing := sp.statements[sp.pos-1].tokens[0]
+				res = append(res, defNode{st: st, body: body, source: sp.source(brace, closing)})
+			}
+		default:
+			res = append(res, commandNode{st: st})
+		}
+	}
+	if open != nil {
+		return nil, errorAt(*open, fmt.Errorf("Unclosed block"))
+	}
+	return res, nil
+}
+
+// source повертає текст між дужками блоку без пропусків на початку та в кінці.
+func (sp *scriptParser) source(open, closing Token) string {
+	if open.Line == closing.Line {
+		return strings.TrimSpace(sp.lines[open.Line-1][open.end:closing.start])
+	}
+	parts := []string{sp.lines[open.Line-1][open.end:]}
+	parts = append(parts, sp.lines[open.Line:closing.Line-1]...)
+	parts = append(parts, sp.lines[closing.Line-1][:closing.start])
+	return strings.TrimSpace(strings.Join(parts, "\n"))
+}
+
+func isKeyword(tok Token, keyword string) bool {
+	return !tok.Quoted && tok.Key == "" && tok.Value == keyword
+}
+
+// run розбирає і виконує скрипт, повертаючи операції для циклу подій.
+func (p *Parser) run(src string) ([]painter.Operation, error) {
+	nodes, err := parseScript(src)
+	if err != nil {
+		return nil, err
+	}
+	var res []painter.Operation
+	return res, p.exec(nodes, &res)
+}
+
+func (p *Parser) exec(nodes []node, res *[]painter.Operation) error {
+	for _, n := range nodes {
+		var err error
+		switch n := n.(type) {
+		case commandNode:
+			if err = p.step(n.st.tokens[0]); err != nil {
+				break
+			}
+			if isKeyword(n.st.tokens[0], "let") {
+				err = p.let(n.st)
+				break
+			}
+			if m, ok := p.macro(n.st.tokens[0]); ok {
+				err = p.call(m, n.st, res)
+				break
+			}
+			var op painter.Operation
+			if op, err = p.process(n.st); op != nil {
+				p.flush(res)
+				*res = append(*res, op)
+			}
+		case defNode:
+			err = p.define(n)
+		case repeatNode:
+			err = p.repeat(n, res)
+		case forNode:
+			err = p.loop(n, res)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// let виконує "let x = вираз" або "let x=вираз".
+func (p *Parser) let(st statement) error {
+	tokens := st.tokens
+	var (
+		name Token
+		expr string
+		at   Token
+	)
+	switch {
+	case len(tokens) == 2 && tokens[1].Key != "":
+		name, expr, at = tokens[1], tokens[1].Value, tokens[1]
+	case len(tokens) >= 4 && tokens[2].Value == "=" && !tokens[2].Quoted:
+		name, expr, at = tokens[1], st.rest(3), tokens[3]
+		name.Key = name.Value
+	default:
+		return errorAt(tokens[0], fmt.Errorf("Expected let name = value"))
+	}
+	if !isIdentifier(name.Key) {
+		return errorAt(name, fmt.Errorf("Invalid variable name"))
+	}
+
+	value, err := evalExpr(expr, p.vars)
+	if err != nil {
+		return errorAt(at, exprError(err, "Invalid value"))
+	}
+	if p.vars == nil {
+		p.vars = make(map[string]float64)
+	}
+	p.vars[name.Key] = value
+	return nil
+}
+
+// repeat виконує "repeat N { ... }".
+func (p *Parser) repeat(n repeatNode, res *[]painter.Operation) error {
+	tokens := n.st.tokens
+	if len(tokens) != 2 || tokens[1].Key != "" {
+		return errorAt(tokens[0], countError{})
+	}
+	count, err := p.evalInt(tokens[1])
+	if err != nil {
+		return err
+	}
+	if count < 0 || count > maxIterations {
+		return errorAt(tokens[1], fmt.Errorf("Repeat count is not in [0,%d] range", maxIterations))
+	}
+	for i := 0; i < count; i++ {
+		if err := p.step(tokens[0]); err != nil {
+			return err
+		}
+		if err := p.exec(n.body, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loop виконує "for i in A..B { ... }". Якщо A більше за B, змінна зменшується. Після циклу змінна отримує попереднє
+// значення.
+func (p *Parser) loop(n forNode, res *[]painter.Operation) error {
+	tokens := n.st.tokens
+	if len(tokens) != 4 || !isKeyword(tokens[2], "in") {
+		return errorAt(tokens[0], fmt.Errorf("Expected for name in from..to"))
+	}
+	name := tokens[1]
+	if name.Quoted || name.Key != "" || !isIdentifier(name.Value) {
+		return errorAt(name, fmt.Errorf("Invalid variable name"))
+	}
+
+	bounds := strings.SplitN(tokens[3].Value, "..", 2)
+	if len(bounds) != 2 {
+		return errorAt(tokens[3], fmt.Errorf("Expected range from..to"))
+	}
+	var from, to int
+	for idx, bound := range bounds {
+		v, err := p.evalInt(Token{Value: bound, Line: tokens[3].Line, Column: tokens[3].Column})
+		if err != nil {
+			return err
+		}
+		if idx == 0 {
+			from = v
+		} else {
+			to = v
+		}
+	}
+	step := 1
+	if from > to {
+		step = -1
+	}
+	if (to-from)*step >= maxIterations {
+		return errorAt(tokens[3], fmt.Errorf("Range is longer than %d", maxIterations))
+	}
+
+	if p.vars == nil {
+		p.vars = make(map[string]float64)
+	}
+	prev, defined := p.vars[name.Value]
+	defer func() {
+		if defined {
+			p.vars[name.Value] = prev
+		} else {
+			delete(p.vars, name.Value)
+		}
+	}()
+	for i := from; ; i += step {
+		p.vars[name.Value] = float64(i)
+		if err := p.step(tokens[0]); err != nil {
+			return err
+		}
+		if err := p.exec(n.body, res); err != nil {
+			return err
+		}
+		if i == to {
+			return nil
+		}
+	}
+}
+
+// step враховує ще один крок виконання скрипту і повертає помилку з позицією tok, якщо ліміт maxSteps вичерпано.
+func (p *Parser) step(tok Token) error {
+	if p.steps++; p.steps > maxSteps {
+		return errorAt(tok, fmt.Errorf("Script takes more than %d steps", maxSteps))
+	}
+	return nil
+}
+
+// evalInt обчислює ціле значення слова, яке може бути числом або виразом.
+func (p *Parser) evalInt(tok Token) (int, error) {
+	v, err := evalExpr(tok.Value, p.vars)
+	if err != nil {
+		return 0, errorAt(tok, exprError(err, "Invalid number"))
+	}
+	if v != math.Trunc(v) || math.Abs(v) > math.MaxInt32 {
+		return 0, errorAt(tok, fmt.Errorf("Value %g is not an integer", v))
+	}
+	return int(v), nil
+}
+
+// exprError замінює синтаксичну помилку виразу на повідомлення msg, залишаючи інші помилки, наприклад про невідому
+// змінну.
+func exprError(err error, msg string) error {
+	if err == errInvalidExpression {
+		return fmt.Errorf("%s", msg)
+	}
+	return err
+}
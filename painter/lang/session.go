@@ -0,0 +1,122 @@
+package lang
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync/atomic"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// SessionHeader - заголовок HTTP запиту з ідентифікатором сесії клієнта. Запити без нього виконуються у сесії за
+// замовчуванням.
+const SessionHeader = "X-Painter-Session"
+
+// Session дає доступ до Parser від імені одного клієнта. Макроси, описані командою "def", видно лише у тій сесії, де
+// їх описали; стан малюнку, змінні та система координат спільні для всіх сесій. Методи Parser з тими ж назвами
+// працюють у сесії з порожнім ідентифікатором.
+type Session struct {
+	p  *Parser
+	id string
+}
+
+// Session повертає сесію з ідентифікатором id. Сесію не потрібно створювати заздалегідь: її макроси зберігаються,
+// доки їх не видалять.
+func (p *Parser) Session(id string) Session {
+	return Session{p: p, id: id}
+}
+
+// ID повертає ідентифікатор сесії.
+func (s Session) ID() string {
+	return s.id
+}
+
+// Parse виконує скрипт так само, як Parser.Parse, але з макросами сесії.
+func (s Session) Parse(in io.Reader) ([]painter.Operation, error) {
+	return s.p.parse(s.id, in)
+}
+
+// ParseJSON виконує JSON команди так само, як Parser.ParseJSON, але з макросами сесії.
+func (s Session) ParseJSON(in io.Reader) ([]painter.Operation, error) {
+	return s.p.parseJSON(s.id, in)
+}
+
+// Macros повертає макроси сесії, впорядковані за назвою.
+func (s Session) Macros() []Macro {
+	s.p.mu.Lock()
+	defer s.p.mu.Unlock()
+	macros := s.p.sessions[s.id]
+	res := make([]Macro, 0, len(macros))
+	for _, m := range macros {
+		res = append(res, Macro{Name: m.Name, Params: append([]string{}, m.Params...), Body: m.Body})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// DeleteMacro видаляє макрос сесії. Повертає false, якщо макросу з такою назвою немає.
+func (s Session) DeleteMacro(name string) bool {
+	s.p.mu.Lock()
+	defer s.p.mu.Unlock()
+	macros, ok := s.p.sessions[s.id]
+	if !ok {
+		return false
+	}
+	_, ok = macros[name]
+	delete(macros, name)
+	if len(macros) == 0 {
+		delete(s.p.sessions, s.id)
+	}
+	return ok
+}
+
+// DeleteMacros видаляє всі макроси сесії.
+func (s Session) DeleteMacros() {
+	s.p.mu.Lock()
+	defer s.p.mu.Unlock()
+	delete(s.p.sessions, s.id)
+}
+
+// enter робить макроси сесії id поточними на час розбору скрипту. Викликається під p.mu; leave повертає змінені
+// макроси у сесію.
+func (p *Parser) enter(id string) {
+	p.session, p.macros = id, p.sessions[id]
+}
+
+func (p *Parser) leave() {
+	if len(p.macros) == 0 {
+		delete(p.sessions, p.session)
+	} else {
+		if p.sessions == nil {
+			p.sessions = make(map[string]map[string]*Macro)
+		}
+		p.sessions[p.session] = p.macros
+	}
+	p.session, p.macros = "", nil
+}
+
+// macrosOf повертає копію макросів сесії id, наприклад щоб заплановане завдання не залежало від змін у сесії.
+func (p *Parser) macrosOf(id string) map[string]*Macro {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	macros := make(map[string]*Macro, len(p.sessions[id]))
+	for name, m := range p.sessions[id] {
+		macros[name] = m
+	}
+	return macros
+}
+
+// connections нумерує з'єднання WebSocket та текстового протоколу, кожне з яких отримує окрему сесію.
+var connections atomic.Int64
+
+// connectionSession створює сесію для нового з'єднання. Її макроси слід видалити, коли з'єднання закриється.
+func connectionSession(p *Parser) Session {
+	return p.Session(fmt.Sprintf("conn-%d", connections.Add(1)))
+}
+
+// requestSession повертає сесію, вказану у заголовку SessionHeader запиту r.
+func requestSession(p *Parser, r *http.Request) Session {
+	return p.Session(r.Header.Get(SessionHeader))
+}
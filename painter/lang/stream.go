@@ -0,0 +1,115 @@
+package lang
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// execLine розбирає рядок скрипту або блок з кількох рядків і надсилає отримані операції у painter.Loop. Повертає
+// повідомлення для клієнта, наприклад результат команди "help".
+func execLine(loop *painter.Loop, s Session, line string) ([]string, error) {
+	ops, err := s.Parse(strings.NewReader(line))
+	if err != nil {
+		return nil, err
+	}
+	ops, msgs := splitMessages(ops)
+	for _, op := range ops {
+		if err := loop.Post(op); err != nil {
+			return nil, err
+		}
+	}
+	return msgs, nil
+}
+
+// pending - відповідь на рядок, який відкриває або продовжує незакритий блок { ... }. Блок виконується, коли
+// надійде рядок з останньою закриваючою дужкою, і відповідь "OK" або "ERR" надсилається на цей рядок.
+const pending = "..."
+
+// block накопичує рядки, поки не закриються всі блоки { ... }, щоб багаторядкові repeat, for та def можна було
+// надсилати по одному рядку, як і через HttpHandler.
+type block struct {
+	lines []string
+	depth int
+}
+
+// add додає рядок і повертає накопичений скрипт, якщо всі блоки у ньому закриті. Рядки з помилками лексера
+// повертаються одразу, щоб Parse повідомив про помилку.
+func (b *block) add(line string) (string, bool) {
+	b.lines = append(b.lines, line)
+	statements, err := lexLine(line, len(b.lines))
+	for _, st := range statements {
+		for _, tok := range st.tokens {
+			if tok.brace && tok.Value == "{" {
+				b.depth++
+			} else if tok.brace {
+				b.depth--
+			}
+		}
+	}
+	if err != nil || b.depth <= 0 {
+		script := strings.Join(b.lines, "\n")
+		b.lines, b.depth = nil, 0
+		return script, true
+	}
+	return "", false
+}
+
+// ack формує відповідь на виконання рядка: "OK" або "ERR <повідомлення>".
+func ack(err error) string {
+	if err != nil {
+		return "ERR " + err.Error()
+	}
+	return "OK"
+}
+
+// ServeLines обслуговує текстовий протокол: кожен рядок, що надходить з rw, виконується як команда скрипту, а у відповідь
+// записується рядок "OK" або "ERR <повідомлення>". Текст, який повертають команди на зразок "help", записується перед
+// "OK". Рядки незакритого блоку { ... } отримують відповідь "..." і виконуються разом з рядком, що закриває блок.
+// Кожен виклик має власну сесію, макроси якої видаляються після завершення. Повертає nil, коли вхідні дані закінчилися.
+func ServeLines(rw io.ReadWriter, loop *painter.Loop, p *Parser) error {
+	session := connectionSession(p)
+	defer session.DeleteMacros()
+	scanner := bufio.NewScanner(rw)
+	scanner.Split(bufio.ScanLines)
+	var b block
+	for scanner.Scan() {
+		script, complete := b.add(scanner.Text())
+		if !complete {
+			if _, err := fmt.Fprintln(rw, pending); err != nil {
+				return err
+			}
+			continue
+		}
+		msgs, err := execLine(loop, session, script)
+		if _, err := fmt.Fprint(rw, strings.Join(msgs, "")); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(rw, ack(err)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// ServeListener приймає з'єднання з l (TCP або Unix сокет) і обслуговує кожне з них через ServeLines в окремій
+// горутині. Повертає помилку, коли l перестає приймати з'єднання.
+func ServeListener(l net.Listener, loop *painter.Loop, p *Parser) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			if err := ServeLines(conn, loop, p); err != nil {
+				log.Printf("Line connection failed: %s", err)
+			}
+		}()
+	}
+}
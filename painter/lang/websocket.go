@@ -0,0 +1,49 @@
+package lang
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"golang.org/x/net/websocket"
+)
+
+// WebSocketHandler конструює обробник WebSocket з'єднань, який дозволяє надсилати команди без створення нового
+// HTTP запиту на кожну з них. Кожне повідомлення містить один або кілька рядків скрипту, які виконуються по черзі через
+// Parser та painter.Loop. На кожен рядок клієнт отримує окреме повідомлення "OK" або "ERR <повідомлення>". Блок
+// { ... } може займати кілька рядків і навіть повідомлень: його рядки отримують відповідь "...", а виконується він
+// разом з рядком, що його закриває. Кожне з'єднання має власну сесію, макроси якої видаляються після його закриття.
+func WebSocketHandler(loop *painter.Loop, p *Parser) http.Handler {
+	// Перевірку заголовка Origin не виконуємо, щоб підключатися могли не лише браузери.
+	return websocket.Server{Handler: func(ws *websocket.Conn) {
+		session := connectionSession(p)
+		defer session.DeleteMacros()
+		var b block
+		for {
+			var msg string
+			if err := websocket.Message.Receive(ws, &msg); err != nil {
+				if err != io.EOF {
+					log.Printf("WebSocket receive failed: %s", err)
+				}
+				return
+			}
+
+			for _, line := range strings.Split(strings.TrimRight(msg, "\n"), "\n") {
+				replies := []string{pending}
+				if script, complete := b.add(line); complete {
+					output, err := execLine(loop, session, script)
+					// Текст, який повертають команди на зразок "help", надсилається окремими повідомленнями перед "OK".
+					replies = append(output, ack(err))
+				}
+				for _, reply := range replies {
+					if err := websocket.Message.Send(ws, reply); err != nil {
+						log.Printf("WebSocket send failed: %s", err)
+						return
+					}
+				}
+			}
+		}
+	}}
+}
@@ -12,13 +12,24 @@ type Receiver interface {
 	Update(t screen.Texture)
 }
 
+// FrameReceiver - Receiver, якому разом з текстурою потрібен стан малюнку, з якого її намальовано, наприклад щоб
+// намалювати кадр ще раз, бо пікселі текстури прочитати не можна. Loop викликає UpdateFrame замість Update.
+type FrameReceiver interface {
+	Receiver
+	UpdateFrame(t screen.Texture, sol StatefulOperationList)
+}
+
 // Loop реалізує цикл подій для формування текстури отриманої через виконання операцій отриманих з внутрішньої черги.
 type Loop struct {
 	Receiver Receiver
 
+	screen screen.Screen // екран, на якому створюються текстури та буфери
+
 	next screen.Texture // текстура, яка зараз формується
 	prev screen.Texture // текстура, яка була відправленя останнього разу у Receiver
 
+	state StatefulOperationList // стан малюнку, останнім намальований на next
+
 	mq messageQueue
 
 	shouldStop bool
@@ -27,8 +38,14 @@ type Loop struct {
 
 var size = image.Pt(800, 800)
 
+// TextureSize повертає розмір текстури, яку створює цикл подій.
+func TextureSize() image.Point {
+	return size
+}
+
 // Start запускає цикл подій. Цей метод потрібно запустити до того, як викликати на ньому будь-які інші методи.
 func (l *Loop) Start(s screen.Screen) {
+	l.screen = s
 	l.next, _ = s.NewTexture(size)
 	l.prev, _ = s.NewTexture(size)
 
@@ -53,14 +70,21 @@ func (l *Loop) Start(s screen.Screen) {
 func beginEventLoop(l *Loop) {
 	for !l.shouldStop || !l.mq.isEmpty() {
 		op := l.mq.pull()
-		update := op.Do(l.next)
+		// Операції отримують текстуру разом з екраном, щоб мати змогу завантажувати у неї зображення.
+		update := op.Do(loopTexture{Texture: l.next, screen: l.screen})
+		if sol, ok := op.(StatefulOperationList); ok {
+			l.state = sol
+		}
 		if update {
-			l.Receiver.Update(l.next)
+			if fr, ok := l.Receiver.(FrameReceiver); ok {
+				fr.UpdateFrame(l.next, l.state)
+			} else {
+				l.Receiver.Update(l.next)
+			}
 			l.next, l.prev = l.prev, l.next
 		}
 	}
 	close(l.finished)
-	l.finished = nil
 }
 
 // Post додає нову операцію у внутрішню чергу.
@@ -79,6 +103,11 @@ func (l *Loop) Post(op Operation) error {
 	return fmt.Errorf("Loop_Post error: operation is nil")
 }
 
+// QueueLen повертає кількість операцій, які чекають на виконання у черзі.
+func (l *Loop) QueueLen() int {
+	return len(l.mq.ch)
+}
+
 // StopAndWait сигналізує про необхідність завершення циклу подій після виконання всіх операцій з черги і чекає на завершення.
 func (l *Loop) StopAndWait() error {
 	// Перевіримо чи цикл подій запущено
@@ -90,6 +119,8 @@ func (l *Loop) StopAndWait() error {
 		l.shouldStop = true
 	}))
 	<-l.finished
+	// Ідентифікатор скидається тут, а не у циклі подій, щоб не змінювати поле одночасно з його читанням у Post.
+	l.finished = nil
 	return nil
 }
 
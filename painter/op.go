@@ -3,7 +3,7 @@ package painter
 import (
 	"image"
 	"image/color"
-	"image/draw"
+	"math"
 
 	"golang.org/x/exp/shiny/screen"
 )
@@ -22,25 +22,53 @@ type StateTweaker interface {
 
 // StatefulOperationList групує операції, що впливають на стан, в одну.
 type StatefulOperationList struct {
-	BgOperation      Operation
-	BgRectOperation  Operation
+	BgOperation Operation
+	// BgRectOperations малюються на фоні у порядку додавання.
+	BgRectOperations []OperationBGRect
 	FigureOperations []*OperationFigure
 }
 
-// Виконує операції відносно до збереженого стану.
+// Виконує операції відносно до збереженого стану. Якщо якась з операцій використовує програмний режим накладання,
+// малюнок формується на Canvas і завантажується у текстуру цілком.
 func (sol StatefulOperationList) Do(t screen.Texture) (ready bool) {
+	if sol.software() && canUpload(t) {
+		canvas := NewCanvas(t.Size())
+		sol.draw(canvas)
+		if upload(t, canvas.RGBA) {
+			return false
+		}
+	}
+	sol.draw(t)
+	return false
+}
+
+// software перевіряє, чи потребує хоча б одна з операцій читання пікселів текстури.
+func (sol StatefulOperationList) software() bool {
+	for _, op := range sol.BgRectOperations {
+		if op.Blend.software() {
+			return true
+		}
+	}
+	for _, op := range sol.FigureOperations {
+		if op.Blend.software() {
+			return true
+		}
+	}
+	return false
+}
+
+func (sol StatefulOperationList) draw(t screen.Texture) {
 	if sol.BgOperation != nil {
 		sol.BgOperation.Do(t)
 	} else {
 		t.Fill(t.Bounds(), color.Black, screen.Src)
 	}
-	if sol.BgRectOperation != nil {
-		sol.BgRectOperation.Do(t)
+	for _, op := range sol.BgRectOperations {
+		op.Do(t)
 	}
 	for _, op := range sol.FigureOperations {
 		op.Do(t)
 	}
-	return false
 }
 
 func (sol *StatefulOperationList) Update(o StateTweaker) {
@@ -88,44 +116,134 @@ func (p RelativePoint) ToAbs(size image.Point) image.Point {
 	}
 }
 
+// MaxBgRects обмежує кількість прямокутників на фоні, щоб періодичні команди не збільшували стан необмежено. Ліміт
+// перевіряють команди, що додають прямокутники (див. lang.Parser.Edit); SetState його не перевіряє.
+const MaxBgRects = 256
+
+// OperationBGRect малює прямокутник фону зі сторонами, паралельними осям. На відміну від фігур, прямокутники не мають
+// перетворення: команди "move", "rotate" та "scale" їх не змінюють.
 type OperationBGRect struct {
 	Min RelativePoint
 	Max RelativePoint
+	// Color - колір прямокутника; якщо не вказаний, прямокутник чорний.
+	Color color.Color
+	Blend BlendMode
+	// Border - товщина рамки у частках ширини текстури. Рамка малюється всередині прямокутника.
+	Border float64
+	// BorderColor - колір рамки; якщо не вказаний, рамка біла.
+	BorderColor color.Color
 }
 
 func (op OperationBGRect) Do(t screen.Texture) bool {
-	minAbs := op.Min.ToAbs(t.Size())
-	maxAbs := op.Max.ToAbs(t.Size())
-
-	rect := image.Rect(minAbs.X, minAbs.Y, maxAbs.X, maxAbs.Y)
-	t.Fill(rect, color.Black, draw.Src)
+	fill, border := op.parts(t.Size())
+	fillRect(t, fill, op.color(), op.Blend)
+	for _, r := range border {
+		fillRect(t, r, op.borderColor(), op.Blend)
+	}
 	return false
 }
 
+func (op OperationBGRect) color() color.Color {
+	if op.Color == nil {
+		return color.Black
+	}
+	return op.Color
+}
+
+func (op OperationBGRect) borderColor() color.Color {
+	if op.BorderColor == nil {
+		return color.White
+	}
+	return op.BorderColor
+}
+
+// bounds повертає прямокутник у пікселях. Координати можуть виходити за межі текстури, тому його частини
+// обрізаються у parts.
+func (op OperationBGRect) bounds(size image.Point) image.Rectangle {
+	minAbs := op.Min.ToAbs(size)
+	maxAbs := op.Max.ToAbs(size)
+	return image.Rect(minAbs.X, minAbs.Y, maxAbs.X, maxAbs.Y)
+}
+
+// parts повертає внутрішню частину прямокутника та частини рамки, обрізані межами текстури. Частини не
+// перекриваються, тому прозорі кольори накладаються лише один раз.
+func (op OperationBGRect) parts(size image.Point) (fill image.Rectangle, border []image.Rectangle) {
+	r := op.bounds(size)
+	width := int(math.Round(op.Border * float64(size.X)))
+	if op.Border > 0 && width == 0 {
+		width = 1
+	}
+	inner := r.Inset(width)
+	clip := image.Rectangle{Max: size}
+	if width > 0 {
+		for _, part := range []image.Rectangle{
+			image.Rect(r.Min.X, r.Min.Y, r.Max.X, inner.Min.Y),
+			image.Rect(r.Min.X, inner.Max.Y, r.Max.X, r.Max.Y),
+			image.Rect(r.Min.X, inner.Min.Y, inner.Min.X, inner.Max.Y),
+			image.Rect(inner.Max.X, inner.Min.Y, r.Max.X, inner.Max.Y),
+		} {
+			if part = part.Intersect(clip); !part.Empty() {
+				border = append(border, part)
+			}
+		}
+	}
+	return inner.Intersect(clip), border
+}
+
 func (op OperationBGRect) SetState(sol *StatefulOperationList) {
-	sol.BgRectOperation = op
+	sol.BgRectOperations = append(sol.BgRectOperations, op)
+}
+
+// ReplaceBGRect видаляє всі прямокутники на фоні і додає Rect.
+type ReplaceBGRect struct {
+	Rect OperationBGRect
 }
 
+func (t ReplaceBGRect) SetState(sol *StatefulOperationList) {
+	sol.BgRectOperations = []OperationBGRect{t.Rect}
+}
+
+// MaxFigures обмежує кількість фігур так само, як MaxBgRects обмежує кількість прямокутників.
+const MaxFigures = 256
+
 type OperationFigure struct {
 	Center RelativePoint
+	// Transform - перетворення фігури навколо її центру; nil означає відсутність перетворення. Фігура з виродженим
+	// перетворенням не малюється.
+	Transform *Matrix
+	// Color - колір фігури; якщо не вказаний, використовується FigureColor.
+	Color color.Color
+	Blend BlendMode
 }
 
-// Функція оптимізована для моєї версії MacOS. Вона не використовує імплементацію з пакету ui, тому що з неявних причин відлік системи координат починається в різних місцях для screen.Texture і screen.Window.
 func (op OperationFigure) Do(t screen.Texture) bool {
-	centerAbs := op.Center.ToAbs(t.Size())
-	x := centerAbs.X
-	y := centerAbs.Y
+	DrawFigure(t, t.Bounds(), op.Center.ToAbs(t.Size()), op.Matrix(), op.color(), op.Blend)
+	return false
+}
 
-	hlen := 115
-	hwidth := 35
-	yellow := color.RGBA{R: 0xff, G: 0xff, A: 0xff}
+// Matrix повертає перетворення фігури або Identity, якщо Transform не вказане.
+func (op OperationFigure) Matrix() Matrix {
+	if op.Transform == nil {
+		return Identity
+	}
+	return *op.Transform
+}
 
-	horizontal := image.Rect(x-hlen, y+hlen, x+hlen, y+hlen-hwidth*2)
-	t.Fill(horizontal, yellow, draw.Src)
-	vertical := image.Rect(x-hwidth, y-hlen, x+hwidth, y+hlen)
-	t.Fill(vertical, yellow, draw.Src)
+// FigureColor - колір фігури за замовчуванням.
+var FigureColor = color.RGBA{R: 0xff, G: 0xff, A: 0xff}
 
-	return false
+func (op OperationFigure) color() color.Color {
+	if op.Color == nil {
+		return FigureColor
+	}
+	return op.Color
+}
+
+// WithAlpha повертає колір c з непрозорістю alpha з проміжку [0,1].
+func WithAlpha(c color.Color, alpha float64) color.Color {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	n.A = uint8(float64(n.A)*alpha + 0.5)
+	return n
 }
 
 func (op OperationFigure) SetState(sol *StatefulOperationList) {
@@ -143,10 +261,37 @@ func (t MoveTweaker) SetState(sol *StatefulOperationList) {
 	}
 }
 
+// MoveFigureTweaker зміщує одну фігуру з номером Index. Якщо такої фігури немає, стан не змінюється.
+type MoveFigureTweaker struct {
+	Index  int
+	Offset RelativePoint
+}
+
+func (t MoveFigureTweaker) SetState(sol *StatefulOperationList) {
+	if t.Index < 0 || t.Index >= len(sol.FigureOperations) {
+		return
+	}
+	op := sol.FigureOperations[t.Index]
+	op.Center.X += t.Offset.X
+	op.Center.Y += t.Offset.Y
+}
+
+// FigureAt повертає номер фігури, що містить точку p, або -1. Якщо фігури перекриваються, повертається та, що
+// намальована останньою.
+func (sol StatefulOperationList) FigureAt(p RelativePoint, size image.Point) int {
+	for idx := len(sol.FigureOperations) - 1; idx >= 0; idx-- {
+		op := sol.FigureOperations[idx]
+		if figureContains(op.Center.ToAbs(size), op.Matrix(), p.X*float64(size.X), p.Y*float64(size.Y)) {
+			return idx
+		}
+	}
+	return -1
+}
+
 type ResetTweaker struct{}
 
 func (op ResetTweaker) SetState(sol *StatefulOperationList) {
 	sol.BgOperation = nil
-	sol.BgRectOperation = nil
+	sol.BgRectOperations = nil
 	sol.FigureOperations = []*OperationFigure{}
 }
@@ -0,0 +1,192 @@
+package painter
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sync"
+
+	"golang.org/x/exp/shiny/screen"
+)
+
+// Pattern задає колір кожної точки фону. Координати передаються у пікселях текстури, щоб клітинки та смуги
+// залишалися пропорційними на неквадратній текстурі.
+type Pattern interface {
+	ColorAt(x, y float64, size image.Point) color.Color
+}
+
+// LinearGradient плавно змінює колір від FromColor у точці From до ToColor у точці To. За межами відрізка колір
+// не змінюється.
+type LinearGradient struct {
+	From, To           RelativePoint
+	FromColor, ToColor color.Color
+}
+
+func (g LinearGradient) ColorAt(x, y float64, size image.Point) color.Color {
+	x1, y1 := g.From.X*float64(size.X), g.From.Y*float64(size.Y)
+	dx, dy := g.To.X*float64(size.X)-x1, g.To.Y*float64(size.Y)-y1
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return g.ToColor
+	}
+	return lerpColor(g.FromColor, g.ToColor, ((x-x1)*dx+(y-y1)*dy)/length)
+}
+
+// RadialGradient плавно змінює колір від Inner у центрі до Outer на відстані Radius (частка ширини текстури).
+type RadialGradient struct {
+	Center       RelativePoint
+	Radius       float64
+	Inner, Outer color.Color
+}
+
+func (g RadialGradient) ColorAt(x, y float64, size image.Point) color.Color {
+	radius := g.Radius * float64(size.X)
+	if radius <= 0 {
+		return g.Outer
+	}
+	d := math.Hypot(x-g.Center.X*float64(size.X), y-g.Center.Y*float64(size.Y))
+	return lerpColor(g.Inner, g.Outer, d/radius)
+}
+
+// Checkerboard - шахова дошка з квадратних клітинок розміром Cell (частка ширини текстури). Ліва верхня клітинка
+// має колір A.
+type Checkerboard struct {
+	Cell float64
+	A, B color.Color
+}
+
+func (c Checkerboard) ColorAt(x, y float64, size image.Point) color.Color {
+	cell := c.Cell * float64(size.X)
+	if cell <= 0 {
+		return c.A
+	}
+	if (int(math.Floor(x/cell))+int(math.Floor(y/cell)))%2 == 0 {
+		return c.A
+	}
+	return c.B
+}
+
+// Stripes - смуги шириною Width (частка ширини текстури), що чергують кольори A та B. Angle задає нахил смуг у
+// радіанах: 0 відповідає вертикальним смугам.
+type Stripes struct {
+	Width float64
+	Angle float64
+	A, B  color.Color
+}
+
+func (s Stripes) ColorAt(x, y float64, size image.Point) color.Color {
+	width := s.Width * float64(size.X)
+	if width <= 0 {
+		return s.A
+	}
+	sin, cos := math.Sincos(s.Angle)
+	if int(math.Floor((x*cos+y*sin)/width))%2 == 0 {
+		return s.A
+	}
+	return s.B
+}
+
+// lerpColor повертає колір між a та b; t обмежується проміжком [0,1].
+func lerpColor(a, b color.Color, t float64) color.Color {
+	t = clamp01(t)
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	mix := func(x, y uint32) uint16 { return uint16(float64(x)*(1-t) + float64(y)*t + 0.5) }
+	return color.RGBA64{R: mix(ar, br), G: mix(ag, bg), B: mix(ab, bb), A: mix(aa, ba)}
+}
+
+// OperationPattern зафарбовує фон візерунком. Зображення візерунку малюється програмно один раз для кожного розміру
+// текстури і завантажується у текстуру через буфер.
+type OperationPattern struct {
+	Pattern Pattern
+
+	cache *patternCache
+}
+
+type patternCache struct {
+	mu  sync.Mutex
+	img *image.RGBA
+}
+
+// PatternFill створює операцію, що зафарбовує фон візерунком p.
+func PatternFill(p Pattern) OperationPattern {
+	return OperationPattern{Pattern: p, cache: &patternCache{}}
+}
+
+func (op OperationPattern) Do(t screen.Texture) bool {
+	size := t.Size()
+	if !upload(t, op.image(size)) {
+		// Текстура не підтримує завантаження зображень, тому використовуємо колір лівого верхнього кута.
+		t.Fill(t.Bounds(), op.Pattern.ColorAt(0.5, 0.5, size), screen.Src)
+	}
+	return false
+}
+
+func (op OperationPattern) image(size image.Point) *image.RGBA {
+	if op.cache != nil {
+		op.cache.mu.Lock()
+		defer op.cache.mu.Unlock()
+		if op.cache.img != nil && op.cache.img.Rect.Size() == size {
+			return op.cache.img
+		}
+	}
+
+	img := image.NewRGBA(image.Rectangle{Max: size})
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			img.Set(x, y, op.Pattern.ColorAt(float64(x)+0.5, float64(y)+0.5, size))
+		}
+	}
+	if op.cache != nil {
+		op.cache.img = img
+	}
+	return img
+}
+
+func (op OperationPattern) SetState(sol *StatefulOperationList) {
+	sol.BgOperation = op
+}
+
+// WriteSVG записує візерунок як SVG градієнт або візерунок, яким зафарбовується прямокутник на всю текстуру.
+func (op OperationPattern) WriteSVG(w io.Writer, size image.Point) error {
+	const id = "background"
+	var defs string
+	switch p := op.Pattern.(type) {
+	case LinearGradient:
+		defs = fmt.Sprintf("<linearGradient id=\"%s\" gradientUnits=\"userSpaceOnUse\" x1=\"%g\" y1=\"%g\" x2=\"%g\" y2=\"%g\">%s%s</linearGradient>",
+			id, p.From.X*float64(size.X), p.From.Y*float64(size.Y), p.To.X*float64(size.X), p.To.Y*float64(size.Y),
+			svgStop(0, p.FromColor), svgStop(1, p.ToColor))
+	case RadialGradient:
+		defs = fmt.Sprintf("<radialGradient id=\"%s\" gradientUnits=\"userSpaceOnUse\" cx=\"%g\" cy=\"%g\" r=\"%g\">%s%s</radialGradient>",
+			id, p.Center.X*float64(size.X), p.Center.Y*float64(size.Y), p.Radius*float64(size.X),
+			svgStop(0, p.Inner), svgStop(1, p.Outer))
+	case Checkerboard:
+		cell := p.Cell * float64(size.X)
+		defs = fmt.Sprintf("<pattern id=\"%s\" patternUnits=\"userSpaceOnUse\" width=\"%g\" height=\"%g\">"+
+			"<rect width=\"%g\" height=\"%g\" %s/><rect x=\"%g\" width=\"%g\" height=\"%g\" %s/>"+
+			"<rect y=\"%g\" width=\"%g\" height=\"%g\" %s/><rect x=\"%g\" y=\"%g\" width=\"%g\" height=\"%g\" %s/></pattern>",
+			id, 2*cell, 2*cell, cell, cell, svgFill(p.A), cell, cell, cell, svgFill(p.B),
+			cell, cell, cell, svgFill(p.B), cell, cell, cell, cell, svgFill(p.A))
+	case Stripes:
+		width := p.Width * float64(size.X)
+		defs = fmt.Sprintf("<pattern id=\"%s\" patternUnits=\"userSpaceOnUse\" width=\"%g\" height=\"1\" patternTransform=\"rotate(%g)\">"+
+			"<rect width=\"%g\" height=\"1\" %s/><rect x=\"%g\" width=\"%g\" height=\"1\" %s/></pattern>",
+			id, 2*width, p.Angle*180/math.Pi, width, svgFill(p.A), width, width, svgFill(p.B))
+	default:
+		return writeSVGRect(w, image.Rectangle{Max: size}, p.ColorAt(0.5, 0.5, size), BlendSrc)
+	}
+	_, err := fmt.Fprintf(w, "  <defs>%s</defs>\n  <rect x=\"0\" y=\"0\" width=\"%d\" height=\"%d\" fill=\"url(#%s)\"/>\n",
+		defs, size.X, size.Y, id)
+	return err
+}
+
+func svgStop(offset float64, c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	stop := fmt.Sprintf("<stop offset=\"%g\" stop-color=\"rgb(%d,%d,%d)\"", offset, n.R, n.G, n.B)
+	if n.A != 0xff {
+		stop += fmt.Sprintf(" stop-opacity=\"%.3f\"", float64(n.A)/0xff)
+	}
+	return stop + "/>"
+}
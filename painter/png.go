@@ -0,0 +1,13 @@
+package painter
+
+import (
+	"image/png"
+	"io"
+)
+
+// ExportPNG малює стан малюнку програмно на Canvas розміру текстури циклу подій і записує його як PNG зображення.
+func ExportPNG(w io.Writer, sol StatefulOperationList) error {
+	canvas := NewCanvas(size)
+	sol.Do(canvas)
+	return png.Encode(w, canvas.RGBA)
+}
@@ -0,0 +1,156 @@
+package painter
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"log"
+	"os"
+	"sync"
+
+	"golang.org/x/exp/shiny/screen"
+)
+
+// Receivers передає кожну готову текстуру всім отримувачам по черзі, що дозволяє одному циклу подій оновлювати
+// кілька вікон та інших отримувачів. Порожні елементи пропускаються.
+type Receivers []Receiver
+
+func (rs Receivers) Update(t screen.Texture) {
+	for _, r := range rs {
+		if r != nil {
+			r.Update(t)
+		}
+	}
+}
+
+// UpdateFrame передає стан кадру тим отримувачам, які реалізують FrameReceiver, а решті - лише текстуру.
+func (rs Receivers) UpdateFrame(t screen.Texture, sol StatefulOperationList) {
+	for _, r := range rs {
+		if fr, ok := r.(FrameReceiver); ok {
+			fr.UpdateFrame(t, sol)
+		} else if r != nil {
+			r.Update(t)
+		}
+	}
+}
+
+// defaultRecordQueue - кількість кадрів, які PNGRecorder за замовчуванням тримає у черзі на запис.
+const defaultRecordQueue = 16
+
+// PNGRecorder зберігає кожен готовий кадр у PNG файл. Пікселі текстури циклу подій прочитати не можна, тому кадр
+// малюється заново зі стану, який Loop передає разом з ним (див. FrameReceiver). Малювання та кодування виконуються
+// в окремій горутині, щоб не затримувати цикл подій; якщо черга заповнена, кадр пропускається. Після завершення
+// запису потрібно викликати Close.
+type PNGRecorder struct {
+	// Path - шаблон шляху файлу з номером кадру, наприклад "frames/%05d.png". Записані кадри нумеруються з 0 без
+	// пропусків, навіть якщо частину кадрів пропущено.
+	Path string
+	// Queue - кількість кадрів, які можуть чекати на запис. Якщо не вказана, використовується 16.
+	Queue int
+
+	mu      sync.Mutex
+	frame   int
+	dropped int
+	frames  chan recordedFrame
+	done    chan struct{}
+}
+
+// recordedFrame - кадр, що чекає на запис: або готове зображення, або стан, з якого його потрібно намалювати.
+type recordedFrame struct {
+	path  string
+	img   *image.RGBA
+	state StatefulOperationList
+	size  image.Point
+}
+
+// Update записує текстуру, пікселі якої можна прочитати (наприклад, Canvas). Для інших текстур без стану кадру
+// записати нічого не можна, тому вони пропускаються.
+func (r *PNGRecorder) Update(t screen.Texture) {
+	src, ok := t.(image.Image)
+	if !ok {
+		return
+	}
+	// Текстура змінюється циклом подій, тому пікселі копіюються до передачі у горутину запису.
+	img := image.NewRGBA(image.Rectangle{Max: t.Size()})
+	draw.Draw(img, img.Rect, src, src.Bounds().Min, draw.Src)
+	r.enqueue(recordedFrame{img: img})
+}
+
+func (r *PNGRecorder) UpdateFrame(t screen.Texture, sol StatefulOperationList) {
+	r.enqueue(recordedFrame{state: sol, size: t.Size()})
+}
+
+func (r *PNGRecorder) enqueue(f recordedFrame) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.frames == nil {
+		queue := r.Queue
+		if queue <= 0 {
+			queue = defaultRecordQueue
+		}
+		r.frames = make(chan recordedFrame, queue)
+		r.done = make(chan struct{})
+		go r.write(r.frames, r.done)
+	}
+	f.path = fmt.Sprintf(r.Path, r.frame)
+	select {
+	case r.frames <- f:
+		r.frame++
+	default:
+		// Пропущені кадри повідомляються один раз, щоб не засмічувати журнал на кожному кадрі.
+		if r.dropped == 0 {
+			log.Printf("Recording can't keep up, frames are dropped")
+		}
+		r.dropped++
+	}
+}
+
+// Dropped повертає кількість кадрів, пропущених через заповнену чергу.
+func (r *PNGRecorder) Dropped() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.dropped
+}
+
+// Close чекає, поки будуть записані всі кадри з черги, і зупиняє горутину запису. Кадри, що надійдуть після цього,
+// записуються у новій горутині з продовженням нумерації.
+func (r *PNGRecorder) Close() {
+	r.mu.Lock()
+	frames, done := r.frames, r.done
+	r.frames, r.done = nil, nil
+	r.mu.Unlock()
+
+	if frames != nil {
+		close(frames)
+		<-done
+	}
+}
+
+func (r *PNGRecorder) write(frames <-chan recordedFrame, done chan<- struct{}) {
+	defer close(done)
+	for f := range frames {
+		img := f.img
+		if img == nil {
+			canvas := NewCanvas(f.size)
+			f.state.Do(canvas)
+			img = canvas.RGBA
+		}
+		if err := writePNG(f.path, img); err != nil {
+			log.Printf("Failed to record frame: %s", err)
+		}
+	}
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,412 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: painter.proto
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Painter_Fill_FullMethodName      = "/painter.Painter/Fill"
+	Painter_BgRect_FullMethodName    = "/painter.Painter/BgRect"
+	Painter_Figure_FullMethodName    = "/painter.Painter/Figure"
+	Painter_Move_FullMethodName      = "/painter.Painter/Move"
+	Painter_Reset_FullMethodName     = "/painter.Painter/Reset"
+	Painter_Update_FullMethodName    = "/painter.Painter/Update"
+	Painter_GetFrame_FullMethodName  = "/painter.Painter/GetFrame"
+	Painter_Subscribe_FullMethodName = "/painter.Painter/Subscribe"
+)
+
+// PainterClient is the client API for Painter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PainterClient interface {
+	// Fill зафарбовує фон у вказаний колір.
+	Fill(ctx context.Context, in *FillRequest, opts ...grpc.CallOption) (*Ack, error)
+	// BgRect малює чорний прямокутник на фоні.
+	BgRect(ctx context.Context, in *BgRectRequest, opts ...grpc.CallOption) (*Ack, error)
+	// Figure додає фігуру з центром у вказаній точці.
+	Figure(ctx context.Context, in *FigureRequest, opts ...grpc.CallOption) (*Ack, error)
+	// Move зміщує всі фігури.
+	Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*Ack, error)
+	// Reset очищує стан малюнку.
+	Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*Ack, error)
+	// Update відображає поточний стан у вікні.
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Ack, error)
+	// GetFrame повертає поточний стан малюнку у вигляді PNG зображення.
+	GetFrame(ctx context.Context, in *GetFrameRequest, opts ...grpc.CallOption) (*Frame, error)
+	// Subscribe надсилає кадр у форматі PNG після кожного оновлення вікна.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Painter_SubscribeClient, error)
+}
+
+type painterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPainterClient(cc grpc.ClientConnInterface) PainterClient {
+	return &painterClient{cc}
+}
+
+func (c *painterClient) Fill(ctx context.Context, in *FillRequest, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, Painter_Fill_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *painterClient) BgRect(ctx context.Context, in *BgRectRequest, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, Painter_BgRect_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *painterClient) Figure(ctx context.Context, in *FigureRequest, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, Painter_Figure_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *painterClient) Move(ctx context.Context, in *MoveRequest, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, Painter_Move_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *painterClient) Reset(ctx context.Context, in *ResetRequest, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, Painter_Reset_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *painterClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Ack, error) {
+	out := new(Ack)
+	err := c.cc.Invoke(ctx, Painter_Update_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *painterClient) GetFrame(ctx context.Context, in *GetFrameRequest, opts ...grpc.CallOption) (*Frame, error) {
+	out := new(Frame)
+	err := c.cc.Invoke(ctx, Painter_GetFrame_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *painterClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Painter_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Painter_ServiceDesc.Streams[0], Painter_Subscribe_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &painterSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Painter_SubscribeClient interface {
+	Recv() (*Frame, error)
+	grpc.ClientStream
+}
+
+type painterSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *painterSubscribeClient) Recv() (*Frame, error) {
+	m := new(Frame)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PainterServer is the server API for Painter service.
+// All implementations must embed UnimplementedPainterServer
+// for forward compatibility
+type PainterServer interface {
+	// Fill зафарбовує фон у вказаний колір.
+	Fill(context.Context, *FillRequest) (*Ack, error)
+	// BgRect малює чорний прямокутник на фоні.
+	BgRect(context.Context, *BgRectRequest) (*Ack, error)
+	// Figure додає фігуру з центром у вказаній точці.
+	Figure(context.Context, *FigureRequest) (*Ack, error)
+	// Move зміщує всі фігури.
+	Move(context.Context, *MoveRequest) (*Ack, error)
+	// Reset очищує стан малюнку.
+	Reset(context.Context, *ResetRequest) (*Ack, error)
+	// Update відображає поточний стан у вікні.
+	Update(context.Context, *UpdateRequest) (*Ack, error)
+	// GetFrame повертає поточний стан малюнку у вигляді PNG зображення.
+	GetFrame(context.Context, *GetFrameRequest) (*Frame, error)
+	// Subscribe надсилає кадр у форматі PNG після кожного оновлення вікна.
+	Subscribe(*SubscribeRequest, Painter_SubscribeServer) error
+	mustEmbedUnimplementedPainterServer()
+}
+
+// UnimplementedPainterServer must be embedded to have forward compatible implementations.
+type UnimplementedPainterServer struct {
+}
+
+func (UnimplementedPainterServer) Fill(context.Context, *FillRequest) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Fill not implemented")
+}
+func (UnimplementedPainterServer) BgRect(context.Context, *BgRectRequest) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BgRect not implemented")
+}
+func (UnimplementedPainterServer) Figure(context.Context, *FigureRequest) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Figure not implemented")
+}
+func (UnimplementedPainterServer) Move(context.Context, *MoveRequest) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Move not implemented")
+}
+func (UnimplementedPainterServer) Reset(context.Context, *ResetRequest) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reset not implemented")
+}
+func (UnimplementedPainterServer) Update(context.Context, *UpdateRequest) (*Ack, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedPainterServer) GetFrame(context.Context, *GetFrameRequest) (*Frame, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFrame not implemented")
+}
+func (UnimplementedPainterServer) Subscribe(*SubscribeRequest, Painter_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedPainterServer) mustEmbedUnimplementedPainterServer() {}
+
+// UnsafePainterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PainterServer will
+// result in compilation errors.
+type UnsafePainterServer interface {
+	mustEmbedUnimplementedPainterServer()
+}
+
+func RegisterPainterServer(s grpc.ServiceRegistrar, srv PainterServer) {
+	s.RegisterService(&Painter_ServiceDesc, srv)
+}
+
+func _Painter_Fill_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FillRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PainterServer).Fill(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Painter_Fill_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PainterServer).Fill(ctx, req.(*FillRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Painter_BgRect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BgRectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PainterServer).BgRect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Painter_BgRect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PainterServer).BgRect(ctx, req.(*BgRectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Painter_Figure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FigureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PainterServer).Figure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Painter_Figure_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PainterServer).Figure(ctx, req.(*FigureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Painter_Move_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PainterServer).Move(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Painter_Move_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PainterServer).Move(ctx, req.(*MoveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Painter_Reset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PainterServer).Reset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Painter_Reset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PainterServer).Reset(ctx, req.(*ResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Painter_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PainterServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Painter_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PainterServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Painter_GetFrame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFrameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PainterServer).GetFrame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Painter_GetFrame_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PainterServer).GetFrame(ctx, req.(*GetFrameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Painter_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PainterServer).Subscribe(m, &painterSubscribeServer{stream})
+}
+
+type Painter_SubscribeServer interface {
+	Send(*Frame) error
+	grpc.ServerStream
+}
+
+type painterSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *painterSubscribeServer) Send(m *Frame) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Painter_ServiceDesc is the grpc.ServiceDesc for Painter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Painter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "painter.Painter",
+	HandlerType: (*PainterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Fill",
+			Handler:    _Painter_Fill_Handler,
+		},
+		{
+			MethodName: "BgRect",
+			Handler:    _Painter_BgRect_Handler,
+		},
+		{
+			MethodName: "Figure",
+			Handler:    _Painter_Figure_Handler,
+		},
+		{
+			MethodName: "Move",
+			Handler:    _Painter_Move_Handler,
+		},
+		{
+			MethodName: "Reset",
+			Handler:    _Painter_Reset_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Painter_Update_Handler,
+		},
+		{
+			MethodName: "GetFrame",
+			Handler:    _Painter_GetFrame_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Painter_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "painter.proto",
+}
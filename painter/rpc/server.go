@@ -0,0 +1,189 @@
+// Package rpc реалізує gRPC сервіс Painter, описаний у painter.proto. Код у файлах *.pb.go згенерований командою
+// "make proto".
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"sync"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"golang.org/x/exp/shiny/screen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server виконує виклики сервісу Painter, змінюючи той самий стан, що й текстові команди Parser, і надсилаючи
+// операції у painter.Loop.
+type Server struct {
+	UnimplementedPainterServer
+
+	Loop   *painter.Loop
+	Parser *lang.Parser
+
+	mu          sync.Mutex
+	subscribers map[chan struct{}]struct{}
+	shown       painter.StatefulOperationList // стан останнього кадру, який цикл подій передав отримувачам
+}
+
+func (s *Server) Fill(ctx context.Context, r *FillRequest) (*Ack, error) {
+	c := r.GetColor()
+	a := uint32(0xff)
+	if c != nil && c.A != nil {
+		a = c.GetA()
+	}
+	if c.GetR() > 0xff || c.GetG() > 0xff || c.GetB() > 0xff || a > 0xff {
+		return nil, status.Error(codes.InvalidArgument, "Color component is not in [0,255] range")
+	}
+	return s.tweak(painter.OperationFill{Color: color.NRGBA{
+		R: uint8(c.GetR()), G: uint8(c.GetG()), B: uint8(c.GetB()), A: uint8(a),
+	}})
+}
+
+func (s *Server) BgRect(ctx context.Context, r *BgRectRequest) (*Ack, error) {
+	min, max := r.GetMin(), r.GetMax()
+	if err := lang.ValidateArguments(min.GetX(), min.GetY(), max.GetX(), max.GetY()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return s.tweak(painter.OperationBGRect{
+		Min: painter.RelativePoint{X: min.GetX(), Y: min.GetY()},
+		Max: painter.RelativePoint{X: max.GetX(), Y: max.GetY()},
+	})
+}
+
+func (s *Server) Figure(ctx context.Context, r *FigureRequest) (*Ack, error) {
+	center := r.GetCenter()
+	if err := lang.ValidateArguments(center.GetX(), center.GetY()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return s.tweak(painter.OperationFigure{
+		Center: painter.RelativePoint{X: center.GetX(), Y: center.GetY()},
+	})
+}
+
+func (s *Server) Move(ctx context.Context, r *MoveRequest) (*Ack, error) {
+	offset := r.GetOffset()
+	if err := lang.ValidateArguments(offset.GetX(), offset.GetY()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return s.tweak(painter.MoveTweaker{
+		Offset: painter.RelativePoint{X: offset.GetX(), Y: offset.GetY()},
+	})
+}
+
+func (s *Server) Reset(ctx context.Context, r *ResetRequest) (*Ack, error) {
+	return s.tweak(painter.ResetTweaker{})
+}
+
+func (s *Server) Update(ctx context.Context, r *UpdateRequest) (*Ack, error) {
+	return s.post(painter.UpdateOp)
+}
+
+func (s *Server) GetFrame(ctx context.Context, r *GetFrameRequest) (*Frame, error) {
+	return s.frame()
+}
+
+func (s *Server) Subscribe(r *SubscribeRequest, stream Painter_SubscribeServer) error {
+	// Канал з буфером на одне повідомлення: повільний клієнт пропускає проміжні кадри, а не блокує цикл подій.
+	updates := make(chan struct{}, 1)
+	s.mu.Lock()
+	if s.subscribers == nil {
+		s.subscribers = make(map[chan struct{}]struct{})
+	}
+	s.subscribers[updates] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, updates)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-updates:
+			frame, err := s.frame()
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(frame); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Notify повертає painter.Receiver, який передає текстури next і сповіщає підписників Subscribe про оновлення.
+// GetFrame та Subscribe кодують стан, з якого намальовано останній кадр, тож клієнт бачить те саме, що й вікно, а
+// не зміни, які ще не дійшли до "update". До першого кадру повертається порожній малюнок.
+func (s *Server) Notify(next painter.Receiver) painter.Receiver {
+	return notifier{server: s, next: next}
+}
+
+type notifier struct {
+	server *Server
+	next   painter.Receiver
+}
+
+func (n notifier) Update(t screen.Texture) {
+	if n.next != nil {
+		n.next.Update(t)
+	}
+	n.server.notify()
+}
+
+func (n notifier) UpdateFrame(t screen.Texture, sol painter.StatefulOperationList) {
+	if fr, ok := n.next.(painter.FrameReceiver); ok {
+		fr.UpdateFrame(t, sol)
+	} else if n.next != nil {
+		n.next.Update(t)
+	}
+
+	n.server.mu.Lock()
+	n.server.shown = sol
+	n.server.mu.Unlock()
+	n.server.notify()
+}
+
+func (s *Server) notify() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// tweak змінює стан так само, як команда скрипту: попередній стан зберігається для "undo", а зміна, що перевищує
+// обмеження на кількість фігур чи прямокутників, відхиляється.
+func (s *Server) tweak(t painter.StateTweaker) (*Ack, error) {
+	op, err := s.Parser.Edit(t, true)
+	if err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return s.post(op)
+}
+
+func (s *Server) post(op painter.Operation) (*Ack, error) {
+	if err := s.Loop.Post(op); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return &Ack{}, nil
+}
+
+func (s *Server) frame() (*Frame, error) {
+	var buf bytes.Buffer
+	s.mu.Lock()
+	shown := s.shown
+	s.mu.Unlock()
+	if err := painter.ExportPNG(&buf, shown); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &Frame{ContentType: "image/png", Data: buf.Bytes()}, nil
+}
@@ -0,0 +1,110 @@
+package painter
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// SVGElement вміє представити себе у вигляді елементів SVG документа.
+type SVGElement interface {
+	// WriteSVG записує елементи для текстури заданого розміру.
+	WriteSVG(w io.Writer, size image.Point) error
+}
+
+// ExportSVG записує стан малюнку як SVG документ з тими ж відносними розмірами, що й у текстури циклу подій.
+// Операції, які не реалізують SVGElement, пропускаються.
+func ExportSVG(w io.Writer, sol StatefulOperationList) error {
+	_, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		size.X, size.Y, size.X, size.Y)
+	if err != nil {
+		return err
+	}
+
+	// Фон за замовчуванням такий самий, як і в StatefulOperationList.Do.
+	var bg Operation = OperationFill{Color: color.Black}
+	if sol.BgOperation != nil {
+		bg = sol.BgOperation
+	}
+	ops := []Operation{bg}
+	for _, op := range sol.BgRectOperations {
+		ops = append(ops, op)
+	}
+	for _, op := range sol.FigureOperations {
+		ops = append(ops, op)
+	}
+
+	for _, op := range ops {
+		if el, ok := op.(SVGElement); ok {
+			if err := el.WriteSVG(w, size); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = io.WriteString(w, "</svg>\n")
+	return err
+}
+
+func (op OperationFill) WriteSVG(w io.Writer, size image.Point) error {
+	return writeSVGRect(w, image.Rectangle{Max: size}, op.Color, BlendSrc)
+}
+
+func (op OperationBGRect) WriteSVG(w io.Writer, size image.Point) error {
+	fill, border := op.parts(size)
+	if err := writeSVGRect(w, fill, op.color(), op.Blend); err != nil {
+		return err
+	}
+	for _, r := range border {
+		if err := writeSVGRect(w, r, op.borderColor(), op.Blend); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (op OperationFigure) WriteSVG(w io.Writer, size image.Point) error {
+	rects, polygons := figureShapes(op.Center.ToAbs(size), op.Matrix(), image.Rectangle{Max: size})
+	for _, r := range rects {
+		if err := writeSVGRect(w, r, op.color(), op.Blend); err != nil {
+			return err
+		}
+	}
+	for _, p := range polygons {
+		if err := p.writeSVG(w, op.color(), op.Blend); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSVGRect(w io.Writer, r image.Rectangle, c color.Color, m BlendMode) error {
+	if r.Empty() {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" %s/>\n",
+		r.Min.X, r.Min.Y, r.Dx(), r.Dy(), svgPaint(c, m))
+	return err
+}
+
+// svgFill перетворює колір у атрибути заливки SVG елемента.
+func svgFill(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	fill := fmt.Sprintf("fill=\"rgb(%d,%d,%d)\"", n.R, n.G, n.B)
+	if n.A != 0xff {
+		fill += fmt.Sprintf(" fill-opacity=\"%.3f\"", float64(n.A)/0xff)
+	}
+	return fill
+}
+
+// svgPaint повертає атрибути заливки разом з режимом накладання. Режими src та over відповідають звичайному
+// малюванню SVG, multiply та screen - однойменним значенням mix-blend-mode; для xor відповідника немає, тому він
+// експортується як over.
+func svgPaint(c color.Color, m BlendMode) string {
+	attrs := svgFill(c)
+	if m == BlendMultiply || m == BlendScreen {
+		attrs += fmt.Sprintf(" style=\"mix-blend-mode:%s\"", m)
+	}
+	return attrs
+}
@@ -0,0 +1,219 @@
+package painter
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/shiny/screen"
+)
+
+// Matrix - лінійне перетворення площини у пікселях: точка (x, y) переходить у (A*x + B*y, C*x + D*y). Нульове значення
+// Matrix - вироджене перетворення, що стискає площину в точку; тотожному перетворенню відповідає Identity.
+type Matrix struct {
+	A, B, C, D float64
+}
+
+// Identity - тотожне перетворення.
+var Identity = Matrix{A: 1, D: 1}
+
+// Rotation повертає поворот на кут angle у радіанах за годинниковою стрілкою (вісь Y текстури спрямована вниз).
+func Rotation(angle float64) Matrix {
+	sin, cos := math.Sincos(angle)
+	return Matrix{A: cos, B: -sin, C: sin, D: cos}
+}
+
+// Scaling повертає рівномірне масштабування з коефіцієнтом factor.
+func Scaling(factor float64) Matrix {
+	return Matrix{A: factor, D: factor}
+}
+
+// MaxScale обмежує збільшення фігури перетворенням: жоден елемент матриці не може перевищувати його за модулем. Так
+// координати вершин залишаються в межах int навіть після багатьох команд "scale".
+const MaxScale = 100
+
+// Degenerate перевіряє, чи є перетворення виродженим, тобто чи стискає воно фігуру у відрізок або точку. Так само
+// розглядаються матриці з нескінченними або невизначеними елементами.
+func (m Matrix) Degenerate() bool {
+	det := m.A*m.D - m.B*m.C
+	return det == 0 || math.IsNaN(det) || math.IsInf(det, 0)
+}
+
+// Drawable перевіряє, чи можна намалювати фігуру з цим перетворенням: воно не вироджене і не збільшує фігуру більше
+// ніж у MaxScale разів.
+func (m Matrix) Drawable() bool {
+	if m.Degenerate() {
+		return false
+	}
+	for _, v := range []float64{m.A, m.B, m.C, m.D} {
+		if math.Abs(v) > MaxScale {
+			return false
+		}
+	}
+	return true
+}
+
+// Mul повертає перетворення, яке спочатку застосовує n, а потім m.
+func (m Matrix) Mul(n Matrix) Matrix {
+	return Matrix{
+		A: m.A*n.A + m.B*n.C, B: m.A*n.B + m.B*n.D,
+		C: m.C*n.A + m.D*n.C, D: m.C*n.B + m.D*n.D,
+	}
+}
+
+// Apply перетворює точку (x, y).
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m.A*x + m.B*y, m.C*x + m.D*y
+}
+
+// axisAligned перевіряє, чи переводить перетворення прямокутники зі сторонами, паралельними осям, у такі ж.
+func (m Matrix) axisAligned() bool {
+	return (m.B == 0 && m.C == 0) || (m.A == 0 && m.D == 0)
+}
+
+// TransformTweaker застосовує лінійне перетворення до всіх фігур. Якщо Pivot не вказаний, кожна фігура
+// перетворюється відносно власного центру, інакше - відносно точки Pivot, тобто змінюються і центри фігур. Фігури,
+// перетворення яких стало б виродженим чи завеликим (див. Matrix.Drawable), залишаються без змін. Прямокутники фону,
+// як і для MoveTweaker, не змінюються.
+type TransformTweaker struct {
+	Matrix Matrix
+	Pivot  *RelativePoint
+}
+
+func (t TransformTweaker) SetState(sol *StatefulOperationList) {
+	size := TextureSize()
+	for _, op := range sol.FigureOperations {
+		m := t.Matrix.Mul(op.Matrix())
+		if !m.Drawable() {
+			continue
+		}
+		// Перетворення замінюється, а не змінюється на місці, бо копії стану, надіслані у цикл подій, посилаються на
+		// ту ж матрицю.
+		op.Transform = &m
+		if t.Pivot == nil {
+			continue
+		}
+		// Центр повертається у пікселях, щоб перетворення не спотворювалося на неквадратній текстурі.
+		dx := (op.Center.X - t.Pivot.X) * float64(size.X)
+		dy := (op.Center.Y - t.Pivot.Y) * float64(size.Y)
+		dx, dy = t.Matrix.Apply(dx, dy)
+		op.Center = RelativePoint{X: t.Pivot.X + dx/float64(size.X), Y: t.Pivot.Y + dy/float64(size.Y)}
+	}
+}
+
+// polygon - многокутник з вершинами у пікселях текстури.
+type polygon []struct{ X, Y float64 }
+
+// transformRect повертає вершини прямокутника r (заданого відносно початку координат) після перетворення m та
+// зсуву на center.
+func transformRect(r image.Rectangle, m Matrix, center image.Point) polygon {
+	corners := []image.Point{r.Min, {X: r.Max.X, Y: r.Min.Y}, r.Max, {X: r.Min.X, Y: r.Max.Y}}
+	res := make(polygon, len(corners))
+	for idx, c := range corners {
+		x, y := m.Apply(float64(c.X), float64(c.Y))
+		res[idx].X, res[idx].Y = x+float64(center.X), y+float64(center.Y)
+	}
+	return res
+}
+
+// extent повертає найменші та найбільші координати вершин многокутника.
+func (p polygon) extent() (minX, minY, maxX, maxY float64) {
+	minX, minY, maxX, maxY = p[0].X, p[0].Y, p[0].X, p[0].Y
+	for _, v := range p[1:] {
+		minX, maxX = math.Min(minX, v.X), math.Max(maxX, v.X)
+		minY, maxY = math.Min(minY, v.Y), math.Max(maxY, v.Y)
+	}
+	return
+}
+
+// bounds повертає найменший прямокутник з цілими координатами, що містить многокутник.
+func (p polygon) bounds() image.Rectangle {
+	minX, minY, maxX, maxY := p.extent()
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
+
+// rect повертає прямокутник з округленими координатами для многокутника, сторони якого паралельні осям.
+func (p polygon) rect() image.Rectangle {
+	minX, minY, maxX, maxY := p.extent()
+	return image.Rect(int(math.Round(minX)), int(math.Round(minY)), int(math.Round(maxX)), int(math.Round(maxY)))
+}
+
+// contains перевіряє, чи лежить точка всередині многокутника.
+func (p polygon) contains(x, y float64) bool {
+	inside := false
+	for idx := range p {
+		a, b := p[idx], p[(idx+1)%len(p)]
+		if (a.Y <= y) != (b.Y <= y) && x < a.X+(y-a.Y)*(b.X-a.X)/(b.Y-a.Y) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// spans повертає горизонтальні відрізки висотою в один піксель, якими заповнюється опуклий многокутник у межах clip.
+// Піксель заповнюється, якщо його центр лежить всередині многокутника.
+func (p polygon) spans(clip image.Rectangle) []image.Rectangle {
+	area := p.bounds().Intersect(clip)
+	var res []image.Rectangle
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		cy := float64(y) + 0.5
+		left, right := math.Inf(1), math.Inf(-1)
+		for idx := range p {
+			a, b := p[idx], p[(idx+1)%len(p)]
+			if (a.Y <= cy) == (b.Y <= cy) {
+				continue
+			}
+			x := a.X + (cy-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+			left, right = math.Min(left, x), math.Max(right, x)
+		}
+		if left > right {
+			continue
+		}
+		span := image.Rect(int(math.Ceil(left-0.5)), y, int(math.Ceil(right-0.5)), y+1).Intersect(clip)
+		if !span.Empty() {
+			res = append(res, span)
+		}
+	}
+	return res
+}
+
+// fillPolygons заповнює многокутники зі спільними сторонами. Відрізки різних многокутників в одному рядку
+// об'єднуються, щоб піксель на спільній стороні не заповнювався двічі через похибку обчислень.
+func fillPolygons(dst screen.Uploader, polygons []polygon, clip image.Rectangle, c color.Color, m BlendMode) {
+	var spans []image.Rectangle
+	for _, p := range polygons {
+		spans = append(spans, p.spans(clip)...)
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].Min.Y != spans[j].Min.Y {
+			return spans[i].Min.Y < spans[j].Min.Y
+		}
+		return spans[i].Min.X < spans[j].Min.X
+	})
+	for idx := 0; idx < len(spans); {
+		span := spans[idx]
+		for idx++; idx < len(spans) && spans[idx].Min.Y == span.Min.Y && spans[idx].Min.X <= span.Max.X; idx++ {
+			if spans[idx].Max.X > span.Max.X {
+				span.Max.X = spans[idx].Max.X
+			}
+		}
+		fillRect(dst, span, c, m)
+	}
+}
+
+func (p polygon) writeSVG(w io.Writer, c color.Color, m BlendMode) error {
+	points := make([]string, len(p))
+	for idx, v := range p {
+		points[idx] = fmt.Sprintf("%g,%g", round2(v.X), round2(v.Y))
+	}
+	_, err := fmt.Fprintf(w, "  <polygon points=\"%s\" %s/>\n", strings.Join(points, " "), svgPaint(c, m))
+	return err
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/shiny/screen"
+)
+
+// runScript виконує скрипт у циклі подій і чекає, поки всі операції з нього будуть оброблені.
+func runScript(t *testing.T, l *painter.Loop, p *lang.Parser, cmd string) {
+	ops, err := p.Parse(strings.NewReader(cmd))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	for _, op := range ops {
+		l.Post(op)
+	}
+	posted := make(chan struct{})
+	l.Post(painter.OperationFunc(func(t screen.Texture) { close(posted) }))
+	<-posted
+}
+
+func newAnimatedParser() (*painter.Loop, *lang.Parser, *painter.Animator) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+	a := &painter.Animator{Loop: &l, State: &p, FrameInterval: time.Millisecond}
+	p.Animator = a
+	return &l, &p, a
+}
+
+func TestAnimator(t *testing.T) {
+	delta := 0.00001
+
+	t.Run("Animation moves figures by the whole offset", func(t *testing.T) {
+		l, p, a := newAnimatedParser()
+		runScript(t, l, p, "figure 0.2 0.2\nanimate move 0.3 -0.1 20 ease-in-out")
+		a.Wait()
+		l.StopAndWait()
+
+		figures := p.State().FigureOperations
+		assert.Equal(t, 1, len(figures))
+		assert.InDelta(t, 0.5, figures[0].Center.X, delta)
+		assert.InDelta(t, 0.1, figures[0].Center.Y, delta)
+	})
+
+	t.Run("Consequent animations are chained", func(t *testing.T) {
+		l, p, a := newAnimatedParser()
+		runScript(t, l, p, "figure 0 0\nanimate move 0.5 0.5 10\nanimate move 0.1 -0.2 10 ease-in")
+		a.Wait()
+		l.StopAndWait()
+
+		figures := p.State().FigureOperations
+		assert.InDelta(t, 0.6, figures[0].Center.X, delta)
+		assert.InDelta(t, 0.3, figures[0].Center.Y, delta)
+	})
+
+	t.Run("Cancelled animation leaves figures in the middle", func(t *testing.T) {
+		l, p, a := newAnimatedParser()
+		runScript(t, l, p, "figure 0 0\nanimate move 1 1 10000\nanimate move -1 -1 10000")
+		time.Sleep(20 * time.Millisecond)
+		runScript(t, l, p, "animate stop")
+		a.Wait()
+		l.StopAndWait()
+
+		figures := p.State().FigureOperations
+		assert.Greater(t, figures[0].Center.X, 0.0)
+		assert.Less(t, figures[0].Center.X, 1.0)
+	})
+}
+
+func TestParser_Animate(t *testing.T) {
+	testTable := []struct {
+		name string
+		cmd  string
+		err  string
+	}{
+		{name: "missing duration", cmd: "animate move 0.1 0.1", err: "Invalid argument count"},
+		{name: "negative duration", cmd: "animate move 0.1 0.1 -5", err: "Value at pos 2 is not positive"},
+		{name: "fractional duration", cmd: "animate move 0.1 0.1 2.5", err: "Invalid argument at pos 2"},
+		{name: "out of range offset", cmd: "animate move 2 0.1 100", err: "Value at pos 0 is not in [-1,1] range"},
+		{name: "unknown easing", cmd: "animate move 0.1 0.1 100 bounce", err: "Unknown easing"},
+		{name: "unknown animation", cmd: "animate spin 100", err: "Unknown animation"},
+		{name: "stop with arguments", cmd: "animate stop now", err: "Invalid argument count"},
+	}
+
+	for _, test := range testTable {
+		p := &lang.Parser{Animator: &painter.Animator{}}
+		_, err := p.Parse(strings.NewReader(test.cmd))
+		assert.EqualError(t, err, test.err, test.name)
+	}
+
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("animate stop"))
+	assert.EqualError(t, err, "Animations are not supported")
+}
+
+// TestAnimator_ConcurrentEdits перевіряє, що цикл подій малює копію стану, поки анімація та інші команди змінюють
+// фігури. Помилки доступу виявляються при запуску з -race (make test-race).
+func TestAnimator_ConcurrentEdits(t *testing.T) {
+	l, p, a := newAnimatedParser()
+	runScript(t, l, p, "figure 0.2 0.2\nfigure 0.6 0.6\nanimate move 0.2 0.2 30\nanimate rotate 90 30")
+	for i := 0; i < 30; i++ {
+		runScript(t, l, p, "move 0.001 0.001\nrotate 1\nscale 1.01")
+	}
+	a.Wait()
+	l.StopAndWait()
+
+	figures := p.State().FigureOperations
+	assert.Equal(t, 2, len(figures))
+	assert.InDelta(t, 0.43, figures[0].Center.X, 0.00001)
+	assert.InDelta(t, 0.83, figures[1].Center.Y, 0.00001)
+}
@@ -0,0 +1,88 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_BgRects(t *testing.T) {
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("bgrect 0 0 0.5 0.5\nbgrect 0.25 0.25 1 1 red border=0.05 border_color=blue"))
+	assert.Nil(t, err)
+	rects := p.State().BgRectOperations
+	assert.Equal(t, 2, len(rects))
+	assert.Nil(t, rects[0].Color)
+	assert.Equal(t, painter.OperationBGRect{
+		Min:         painter.RelativePoint{X: 0.25, Y: 0.25},
+		Max:         painter.RelativePoint{X: 1, Y: 1},
+		Color:       color.RGBA{R: 0xff, A: 0xff},
+		Border:      0.05,
+		BorderColor: color.RGBA{B: 0xff, A: 0xff},
+	}, rects[1])
+
+	// Прямокутники малюються у порядку додавання, рамка - всередині прямокутника.
+	tx := newImageTexture(image.Pt(200, 200))
+	_, err = p.Parse(strings.NewReader("white"))
+	assert.Nil(t, err)
+	p.State().Do(tx)
+	assert.Equal(t, color.RGBA{A: 0xff}, tx.RGBAAt(10, 10))
+	assert.Equal(t, color.RGBA{B: 0xff, A: 0xff}, tx.RGBAAt(55, 60))
+	assert.Equal(t, color.RGBA{R: 0xff, A: 0xff}, tx.RGBAAt(65, 65))
+	assert.Equal(t, color.RGBA{B: 0xff, A: 0xff}, tx.RGBAAt(199, 199))
+	assert.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}, tx.RGBAAt(150, 10))
+
+	_, err = p.Parse(strings.NewReader("bgrect 0.1 0.1 0.2 0.2 green mode=replace"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(p.State().BgRectOperations))
+
+	_, err = p.Parse(strings.NewReader("bgrect 0 0 1 1 border=-0.1"))
+	assert.EqualError(t, err, "Value at pos 7 is not positive")
+}
+
+func TestParser_BgRectLimit(t *testing.T) {
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("repeat 256 { bgrect 0 0 0.5 0.5 }"))
+	assert.Nil(t, err)
+	assert.Equal(t, painter.MaxBgRects, len(p.State().BgRectOperations))
+
+	// Прямокутник понад ліміт не видаляє найстаріші, а відхиляє весь скрипт.
+	_, err = p.Parse(strings.NewReader("figure 0.5 0.5\nbgrect 0.5 0.5 1 1"))
+	var parseErr *lang.ParseError
+	if assert.True(t, errors.As(err, &parseErr)) {
+		assert.Equal(t, "Background already has 256 rectangles", parseErr.Error())
+		assert.Equal(t, 2, parseErr.Line)
+	}
+	_, err = p.Edit(painter.OperationBGRect{}, true)
+	assert.EqualError(t, err, "Background already has 256 rectangles")
+	st := p.State()
+	assert.Equal(t, painter.MaxBgRects, len(st.BgRectOperations))
+	assert.Equal(t, 0, len(st.FigureOperations))
+
+	_, err = p.Parse(strings.NewReader("bgrect 0.5 0.5 1 1 mode=replace\nbgrect 0 0 0.5 0.5"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(p.State().BgRectOperations))
+
+	c, _ := lang.DefaultRegistry.Lookup("bgrect")
+	assert.Contains(t, c.Description, "не більше 256 прямокутників")
+}
+
+func TestExportSVG_BgRectBorder(t *testing.T) {
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("bgrect 0.25 0.25 0.75 0.75 transparent border=0.01"))
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	assert.Nil(t, painter.ExportSVG(&buf, p.State()))
+	out := buf.String()
+	assert.Contains(t, out, `<rect x="208" y="208" width="384" height="384" fill="rgb(0,0,0)" fill-opacity="0.000"/>`)
+	assert.Contains(t, out, `<rect x="200" y="200" width="400" height="8" fill="rgb(255,255,255)"/>`)
+	assert.Equal(t, 6, strings.Count(out, "<rect"))
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Blend(t *testing.T) {
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("figure 0.5 0.5 alpha=0.5 blend=multiply\nbgrect 0 0 1 1 alpha=0.25"))
+	assert.Nil(t, err)
+	st := p.State()
+	assert.Equal(t, color.NRGBA{R: 0xff, G: 0xff, A: 0x80}, st.FigureOperations[0].Color)
+	assert.Equal(t, painter.BlendMultiply, st.FigureOperations[0].Blend)
+	assert.Equal(t, painter.OperationBGRect{
+		Max:   painter.RelativePoint{X: 1, Y: 1},
+		Color: color.NRGBA{A: 0x40},
+	}, st.BgRectOperations[0])
+
+	_, err = p.Parse(strings.NewReader("figure 0.5 0.5 blend=lighten"))
+	assert.EqualError(t, err, "Unknown blend")
+	_, err = p.Parse(strings.NewReader("figure 0.5 0.5 alpha=2"))
+	assert.EqualError(t, err, "Value at pos 2 is not in [0,1] range")
+
+	// У JSON необов'язкові аргументи можна пропускати не лише в кінці.
+	p = &lang.Parser{}
+	_, err = p.ParseJSON(strings.NewReader(`[{"op": "figure", "x": 0.5, "y": 0.5, "blend": "screen"}]`))
+	assert.Nil(t, err)
+	assert.Equal(t, painter.BlendScreen, p.State().FigureOperations[0].Blend)
+	assert.Nil(t, p.State().FigureOperations[0].Color)
+}
+
+func TestStatefulOperationList_Blend(t *testing.T) {
+	testTable := []struct {
+		name   string
+		figure string
+		color  color.RGBA
+	}{
+		{name: "over", figure: "figure 0.5 0.5", color: color.RGBA{R: 0xff, G: 0xff, A: 0xff}},
+		{name: "over with alpha", figure: "figure 0.5 0.5 0.5", color: color.RGBA{R: 0x80, G: 0xff, A: 0xff}},
+		{name: "multiply", figure: "figure 0.5 0.5 blend=multiply", color: color.RGBA{G: 0xff, A: 0xff}},
+		{name: "screen", figure: "figure 0.5 0.5 blend=screen", color: color.RGBA{R: 0xff, G: 0xff, A: 0xff}},
+		{name: "xor", figure: "figure 0.5 0.5 blend=xor", color: color.RGBA{}},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			p := &lang.Parser{}
+			_, err := p.Parse(strings.NewReader("green\n" + test.figure))
+			assert.Nil(t, err)
+
+			tx := newImageTexture(image.Pt(800, 800))
+			p.State().Do(tx)
+			assert.Equal(t, test.color, tx.RGBAAt(400, 400))
+			// Пікселі поза фігурою не змінюються.
+			assert.Equal(t, color.RGBA{G: 0xff, A: 0xff}, tx.RGBAAt(10, 10))
+		})
+	}
+}
+
+func TestExportSVG_Blend(t *testing.T) {
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("figure 0.5 0.5 0.5 multiply"))
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	assert.Nil(t, painter.ExportSVG(&buf, p.State()))
+	assert.Equal(t, 2, strings.Count(buf.String(), `fill="rgb(255,255,0)" fill-opacity="0.502" style="mix-blend-mode:multiply"`))
+}
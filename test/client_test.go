@@ -0,0 +1,150 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/client"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+	s := &lang.Scheduler{Loop: &l, Parser: &p}
+	p.Scheduler = s
+	defer s.CancelAll()
+
+	mux := http.NewServeMux()
+	mux.Handle("/frame.svg", lang.SVGHandler(&p))
+	mux.Handle("/jobs", lang.JobsHandler(s))
+	mux.Handle("/jobs/", lang.JobsHandler(s))
+	mux.Handle("/", lang.HttpHandler(&l, &p))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	c := client.New(server.URL + "/")
+
+	assert.Nil(t, c.Send(ctx, client.Green(), client.BgRect(0.1, 0.1, 0.9, 0.9), client.Figure(0.5, 0.5)))
+	assert.Nil(t, c.Move(ctx, 0.1, -0.1))
+	assert.Nil(t, c.Update(ctx))
+
+	out, err := c.SendScript(ctx, "help move\nupdate")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(out, "move dx dy\n"))
+	out, err = c.SendScript(ctx, "update")
+	assert.Nil(t, err)
+	assert.Equal(t, "", out)
+
+	err = c.Send(ctx, client.Move(0.1, 0.1), client.Figure(0.5, 1.5))
+	var painterErr *client.Error
+	assert.True(t, errors.As(err, &painterErr))
+	assert.Equal(t, http.StatusBadRequest, painterErr.StatusCode)
+	assert.Equal(t, 1, painterErr.Command)
+	assert.Equal(t, "Command 1: Value at pos 1 is not in [-1,1] range", painterErr.Message)
+
+	err = c.AnimateStop(ctx)
+	assert.True(t, errors.As(err, &painterErr))
+	assert.Equal(t, "Command 0: Animations are not supported", painterErr.Message)
+
+	assert.Nil(t, c.Every(ctx, time.Hour, client.Move(0.01, 0)))
+	assert.Nil(t, c.After(ctx, time.Hour, client.Reset()))
+	assert.Nil(t, c.After(ctx, time.Hour, client.Command{Op: "figure", Named: map[string]any{"y": 0.25, "x": 0.5}}))
+	// Завдання реєструються циклом подій, тому чекаємо на їх появу.
+	assert.Eventually(t, func() bool {
+		jobs, err := c.Jobs(ctx)
+		return err == nil && len(jobs) == 3
+	}, time.Second, time.Millisecond)
+	jobs, _ := c.Jobs(ctx)
+	assert.Equal(t, "move 0.01 0", jobs[0].Command)
+	assert.Equal(t, "figure x=0.5 y=0.25", jobs[2].Command)
+	assert.Nil(t, c.CancelJob(ctx, jobs[0].ID))
+	err = c.CancelJob(ctx, jobs[0].ID)
+	assert.True(t, errors.As(err, &painterErr))
+	assert.Equal(t, http.StatusNotFound, painterErr.StatusCode)
+	assert.Equal(t, -1, painterErr.Command)
+	assert.Nil(t, c.CancelJobs(ctx))
+
+	frame, err := c.Frame(ctx)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(frame), "<svg "))
+	assert.Contains(t, string(frame), `fill="rgb(0,255,0)"`)
+
+	l.StopAndWait()
+	figures := p.State().FigureOperations
+	assert.Equal(t, 1, len(figures))
+	assert.InDelta(t, 0.6, figures[0].Center.X, 0.00001)
+	assert.InDelta(t, 0.4, figures[0].Center.Y, 0.00001)
+}
+
+func TestClient_Commands(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+	defer l.StopAndWait()
+	server := httptest.NewServer(lang.HttpHandler(&l, &p))
+	defer server.Close()
+
+	ctx := context.Background()
+	c := client.New(server.URL)
+
+	assert.Nil(t, c.LinearGradient(ctx, 0, 0, 1, 1, color.White, color.Black))
+	assert.Nil(t, c.RadialGradient(ctx, 0.5, 0.5, 0.5, color.White, color.Black))
+	assert.Nil(t, c.Checkerboard(ctx, 0.1, color.White, color.Black))
+	assert.Nil(t, c.Stripes(ctx, 0.1, color.White, color.Black, 45))
+	if op, ok := p.State().BgOperation.(painter.OperationPattern); assert.True(t, ok) {
+		assert.Equal(t, painter.Stripes{Width: 0.1, Angle: math.Pi / 4, A: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+			B: color.NRGBA{A: 0xff}}, op.Pattern)
+	}
+
+	assert.Nil(t, c.BgRect(ctx, 0, 0, 0.2, 0.2))
+	assert.Nil(t, c.BgRectWith(ctx, 0.1, 0.1, 0.5, 0.5, client.RectOptions{
+		Color: color.NRGBA{R: 0xff, A: 0x80}, Blend: "xor", Border: 0.01, BorderColor: color.White, Replace: true,
+	}))
+	assert.Equal(t, []painter.OperationBGRect{{
+		Min:         painter.RelativePoint{X: 0.1, Y: 0.1},
+		Max:         painter.RelativePoint{X: 0.5, Y: 0.5},
+		Color:       color.NRGBA{R: 0xff, A: 0x80},
+		Blend:       painter.BlendXor,
+		Border:      0.01,
+		BorderColor: color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}}, p.State().BgRectOperations)
+
+	assert.Nil(t, c.Figure(ctx, 0.5, 0.5))
+	assert.Nil(t, c.Rotate(ctx, 90))
+	assert.Nil(t, c.Scale(ctx, 2))
+	assert.Equal(t, painter.Matrix{C: 2, B: -2}, roundMatrix(p.State().FigureOperations[0].Matrix()))
+	assert.Nil(t, c.Send(ctx, client.RotateAround(90, 0.25, 0.5), client.ScaleAround(0.5, 0.25, 0.5)))
+	figure := p.State().FigureOperations[0]
+	assert.InDelta(t, 0.25, figure.Center.X, 1e-9)
+	assert.InDelta(t, 0.625, figure.Center.Y, 1e-9)
+
+	// Система координат діє на наступні команди, тому координати фігури задаються у пікселях.
+	assert.Nil(t, c.Send(ctx, client.PixelCoords(), client.Figure(200, 600), client.RelativeCoords()))
+	assert.Equal(t, painter.RelativePoint{X: 0.25, Y: 0.75}, p.State().FigureOperations[1].Center)
+	assert.Nil(t, c.Send(ctx, client.ViewportCoords(-1, 1, 1, -1), client.Figure(0, 0), client.RelativeCoords()))
+	assert.Equal(t, painter.RelativePoint{X: 0.5, Y: 0.5}, p.State().FigureOperations[2].Center)
+
+	assert.Nil(t, c.Undo(ctx))
+	assert.Nil(t, c.Undo(ctx))
+	assert.Equal(t, 1, len(p.State().FigureOperations))
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Coords(t *testing.T) {
+	t.Run("Pixel coordinates", func(t *testing.T) {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader("coords pixel\nbgrect 200 -400 1000 600\nfigure 400 200\nmove -80 40"))
+		assert.Nil(t, err)
+		st := p.State()
+		assert.Equal(t, painter.OperationBGRect{
+			Min: painter.RelativePoint{X: 0.25, Y: -0.5},
+			Max: painter.RelativePoint{X: 1.25, Y: 0.75},
+		}, st.BgRectOperations[0])
+		assert.Equal(t, []painter.RelativePoint{{X: 0.4, Y: 0.3}}, figureCenters(p))
+
+		// Система координат зберігається між запитами.
+		assert.Equal(t, "pixel", p.Coords().Mode)
+		_, err = p.Parse(strings.NewReader("figure 1700 0"))
+		assert.EqualError(t, err, "Value at pos 0 is not in [-800,1600] range")
+		_, err = p.Parse(strings.NewReader("move 2500 0"))
+		assert.EqualError(t, err, "Value at pos 0 is not in [-2400,2400] range")
+	})
+
+	t.Run("Viewport coordinates", func(t *testing.T) {
+		p := &lang.Parser{}
+		ops, err := p.Parse(strings.NewReader("coords viewport -10 10 10 -10\nfigure 5 5\nfigure y=-10 x=-10\nmove 10 0\ncoords"))
+		assert.Nil(t, err)
+		assert.Equal(t, []painter.RelativePoint{{X: 1.25, Y: 0.25}, {X: 0.5, Y: 1}}, figureCenters(p))
+		assert.Equal(t, lang.Message("viewport -10 10 10 -10\n"), ops[len(ops)-1])
+
+		_, err = p.Parse(strings.NewReader("figure 0 31"))
+		assert.EqualError(t, err, "Value at pos 1 is not in [-30,30] range")
+	})
+
+	t.Run("Relative coordinates keep the old range", func(t *testing.T) {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader("coords pixel\ncoords relative\nfigure 1.5 0"))
+		assert.EqualError(t, err, "Value at pos 0 is not in [-1,1] range")
+	})
+
+	testTable := []struct {
+		script string
+		err    string
+	}{
+		{script: "coords viewport 0 0 1", err: "Invalid argument count"},
+		{script: "coords pixel 0 0 1 1", err: "Invalid argument count"},
+		{script: "coords viewport 0 0 0 1", err: "Viewport has zero size"},
+		{script: "coords polar", err: "Unknown mode"},
+	}
+	for _, test := range testTable {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader(test.script))
+		assert.EqualError(t, err, test.err, test.script)
+	}
+
+	t.Run("Failed script keeps the previous coordinate system", func(t *testing.T) {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader("coords pixel\nfigure 2 2 2"))
+		assert.NotNil(t, err)
+		assert.Equal(t, "relative", p.Coords().Mode)
+	})
+}
+
+func TestOperations_Clipping(t *testing.T) {
+	st := painter.StatefulOperationList{
+		BgRectOperations: []painter.OperationBGRect{{
+			Min: painter.RelativePoint{X: -0.5, Y: 0.5},
+			Max: painter.RelativePoint{X: 0.5, Y: 1.5},
+		}},
+		FigureOperations: []*painter.OperationFigure{
+			{Center: painter.RelativePoint{X: 1.1, Y: 0.5}},
+			{Center: painter.RelativePoint{X: -0.5, Y: -0.5}},
+		},
+	}
+	var buf bytes.Buffer
+	assert.Nil(t, painter.ExportSVG(&buf, st))
+	out := buf.String()
+
+	assert.Contains(t, out, `<rect x="0" y="400" width="400" height="400" fill="rgb(0,0,0)"/>`)
+	// Від першої фігури видно лише ліву частину горизонтальної планки, друга повністю за межами текстури.
+	assert.Contains(t, out, `<rect x="765" y="285" width="35" height="70" fill="rgb(255,255,0)"/>`)
+	assert.Equal(t, 3, strings.Count(out, "<rect"))
+}
@@ -0,0 +1,74 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/MytsV/architecture-lab-3/ui"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/shiny/screen"
+)
+
+func TestParser_LastCommand(t *testing.T) {
+	p := &lang.Parser{}
+	assert.Equal(t, "", p.LastCommand())
+
+	_, err := p.Parse(strings.NewReader("white\nbgrect 0.1 0.1 0.5 0.5 color=\"#ff0000\"\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, `bgrect 0.1 0.1 0.5 0.5 color="#ff0000"`, p.LastCommand())
+
+	// Команда запам'ятовується, навіть якщо вона завершилася помилкою.
+	_, err = p.Parse(strings.NewReader("move 3 3"))
+	assert.NotNil(t, err)
+	assert.Equal(t, "move 3 3", p.LastCommand())
+
+	// Для макросів показується остання команда з тіла макросу у тому вигляді, в якому вона записана.
+	_, err = p.Parse(strings.NewReader("def at(x, y) { figure $x $y }\nat 0.25 0.75"))
+	assert.Nil(t, err)
+	assert.Equal(t, "figure $x $y", p.LastCommand())
+}
+
+func TestLoop_QueueLen(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+	)
+	l.Receiver = &tr
+	assert.Equal(t, 0, l.QueueLen())
+	l.Start(mockScreen{})
+
+	// Поки перша операція не завершилася, решта чекає у черзі.
+	started, release := make(chan struct{}), make(chan struct{})
+	l.Post(mockOperationFunc(func(screen.Texture) {
+		close(started)
+		<-release
+	}))
+	<-started
+	for i := 0; i < 3; i++ {
+		l.Post(mockFillOperation{})
+	}
+	assert.Equal(t, 3, l.QueueLen())
+
+	close(release)
+	l.StopAndWait()
+	assert.Equal(t, 0, l.QueueLen())
+}
+
+func TestDebugInfo_Lines(t *testing.T) {
+	info := ui.DebugInfo{
+		FPS:         60,
+		Dropped:     2,
+		Mouse:       painter.RelativePoint{X: 0.5, Y: 0.25},
+		QueueLen:    7,
+		LastCommand: "bgrect 0.1 0.1 0.5 0.5 color=red alpha=0.5 blend=multiply",
+	}
+	assert.Equal(t, []string{
+		"FPS: 60",
+		"Queue: 7",
+		"Dropped: 2",
+		"Last: bgrect 0.1 0.1 0.5 0.5 color=red ...",
+		"Mouse: 0.500, 0.250",
+	}, info.Lines())
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Undo(t *testing.T) {
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("undo"))
+	assert.EqualError(t, err, "Nothing to undo")
+
+	for _, script := range []string{"figure 0.25 0.1", "help", "move 0.25 0; figure 0.5 0.5", "figure 2 2"} {
+		_, _ = p.Parse(strings.NewReader(script))
+	}
+	assert.Equal(t, []painter.RelativePoint{{X: 0.5, Y: 0.1}, {X: 0.5, Y: 0.5}}, figureCenters(p))
+
+	// Скрипти, що не змінили стан або завершилися помилкою, не потрапляють в історію.
+	ops, err := p.Parse(strings.NewReader("undo"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ops))
+	assert.Equal(t, []painter.RelativePoint{{X: 0.25, Y: 0.1}}, figureCenters(p))
+	_, err = p.Parse(strings.NewReader("undo; undo"))
+	assert.EqualError(t, err, "Nothing to undo")
+	assert.Equal(t, []painter.RelativePoint{{X: 0.25, Y: 0.1}}, figureCenters(p))
+}
+
+func TestEditor(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+	defer l.StopAndWait()
+	e := lang.Editor{Loop: &l, Parser: &p}
+
+	assert.Nil(t, e.PlaceFigure(painter.RelativePoint{X: 0.5, Y: 0.5}))
+	assert.Nil(t, e.PlaceFigure(painter.RelativePoint{X: 0.1, Y: 0.1}))
+	// Центр фігури та її перекладина належать фігурі, кут поруч з ніжкою - ні.
+	assert.Equal(t, 0, e.FigureAt(painter.RelativePoint{X: 0.5, Y: 0.5}))
+	assert.Equal(t, 0, e.FigureAt(painter.RelativePoint{X: 0.6, Y: 0.4}))
+	assert.Equal(t, -1, e.FigureAt(painter.RelativePoint{X: 0.6, Y: 0.6}))
+	assert.Equal(t, 1, e.FigureAt(painter.RelativePoint{X: 0.1, Y: 0.1}))
+
+	// Перетягування скасовується цілком.
+	assert.Nil(t, e.MoveFigure(0, painter.RelativePoint{X: 0.1}, true))
+	assert.Nil(t, e.MoveFigure(0, painter.RelativePoint{Y: 0.1}, false))
+	assert.Equal(t, []painter.RelativePoint{{X: 0.6, Y: 0.6}, {X: 0.1, Y: 0.1}}, figureCenters(&p))
+	assert.Nil(t, e.Undo())
+	assert.Equal(t, []painter.RelativePoint{{X: 0.5, Y: 0.5}, {X: 0.1, Y: 0.1}}, figureCenters(&p))
+
+	// Зміни з вікна і зі скриптів потрапляють в одну історію.
+	assert.Nil(t, e.Reset())
+	_, err := p.Parse(strings.NewReader("undo"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(figureCenters(&p)))
+	assert.Nil(t, e.Undo())
+	assert.Nil(t, e.Undo())
+	assert.Equal(t, 0, len(figureCenters(&p)))
+	assert.EqualError(t, e.Undo(), "Nothing to undo")
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/MytsV/architecture-lab-3/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventsHandler(t *testing.T) {
+	var ev lang.Events
+	server := httptest.NewServer(lang.EventsHandler(&ev))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Заголовки відправляються після підписки, тому події, опубліковані далі, не губляться.
+	ev.Publish(ui.Event{Type: ui.EventClick, At: &ui.Point{X: 0.25, Y: 0}, Button: "left"})
+	ev.Publish(ui.Event{Type: ui.EventKey, At: &ui.Point{X: 0.5, Y: 0.5}, Key: "A", Rune: "a", Modifiers: []string{"shift"}})
+	ev.Publish(ui.Event{Type: ui.EventResize, Width: 400, Height: 300})
+	ev.Publish(ui.Event{Type: ui.EventClose})
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				lines <- line
+			}
+		}
+		close(lines)
+	}()
+	for _, expected := range []string{
+		`data: {"type":"click","at":{"x":0.25,"y":0},"button":"left"}`,
+		`data: {"type":"key","at":{"x":0.5,"y":0.5},"key":"A","rune":"a","modifiers":["shift"]}`,
+		`data: {"type":"resize","width":400,"height":300}`,
+		`data: {"type":"close"}`,
+	} {
+		select {
+		case line := <-lines:
+			assert.Equal(t, expected, line)
+		case <-time.After(time.Second):
+			t.Fatalf("event %s was not received", expected)
+		}
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(""))
+	resp, err = http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestEvents_SlowSubscriber(t *testing.T) {
+	var ev lang.Events
+	events, cancel := ev.Subscribe()
+
+	// Повільний підписник не блокує Publish, а зайві події відкидаються.
+	for i := 0; i < 1000; i++ {
+		ev.Publish(ui.Event{Type: ui.EventClose})
+	}
+	assert.Equal(t, 64, len(events))
+
+	cancel()
+	ev.Publish(ui.Event{Type: ui.EventClose})
+	assert.Equal(t, 64, len(events))
+}
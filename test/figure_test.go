@@ -0,0 +1,91 @@
+package test
+
+import (
+	"flag"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateGolden = flag.Bool("update", false, "перезаписати еталонні зображення у testdata")
+
+func TestFigure_Golden(t *testing.T) {
+	testTable := []struct {
+		name string
+		file string
+		op   painter.OperationFigure
+	}{
+		{name: "centered", file: "figure.png", op: painter.OperationFigure{Center: painter.RelativePoint{X: 0.5, Y: 0.5}}},
+		{name: "clipped", file: "figure_clipped.png", op: painter.OperationFigure{Center: painter.RelativePoint{X: 0.95, Y: 0.05}}},
+		{name: "rotated", file: "figure_rotated.png", op: painter.OperationFigure{
+			Center:    painter.RelativePoint{X: 0.25, Y: 0.75},
+			Transform: transform(painter.Rotation(math.Pi / 6)),
+		}},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			size := image.Pt(800, 800)
+			tx := newImageTexture(size)
+			test.op.Do(tx)
+
+			path := filepath.Join("testdata", test.file)
+			if *updateGolden {
+				f, err := os.Create(path)
+				if assert.Nil(t, err) {
+					assert.Nil(t, png.Encode(f, tx.RGBA))
+					assert.Nil(t, f.Close())
+				}
+			}
+			f, err := os.Open(path)
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer f.Close()
+			golden, err := png.Decode(f)
+			if !assert.Nil(t, err) {
+				return
+			}
+			expected := image.NewRGBA(golden.Bounds())
+			draw.Draw(expected, expected.Rect, golden, golden.Bounds().Min, draw.Src)
+			assert.Equal(t, expected.Pix, tx.Pix, "output differs from %s; run go test ./test -run TestFigure_Golden -update", path)
+		})
+	}
+}
+
+func TestFigure_Translucent(t *testing.T) {
+	// Перекладина та ніжка не перекриваються, тому кожен піксель фігури накладається на фон один раз.
+	for _, transform := range []*painter.Matrix{nil, transform(painter.Rotation(math.Pi / 6))} {
+		canvas := painter.NewCanvas(image.Pt(800, 800))
+		painter.StatefulOperationList{FigureOperations: []*painter.OperationFigure{{
+			Center:    painter.RelativePoint{X: 0.5, Y: 0.5},
+			Transform: transform,
+			Color:     painter.WithAlpha(painter.FigureColor, 0.5),
+		}}}.Do(canvas)
+
+		single := canvas.RGBAAt(400, 450)
+		assert.Equal(t, uint8(128), single.R)
+		for y := 0; y < 800; y++ {
+			for x := 0; x < 800; x++ {
+				if px := canvas.RGBAAt(x, y); px.R != 0 && px != single {
+					t.Fatalf("pixel (%d, %d) is %v, expected %v", x, y, px, single)
+				}
+			}
+		}
+	}
+
+	// Повторне накладання у режимі xor повернуло б фон у місці з'єднання перекладини та ніжки.
+	canvas := painter.NewCanvas(image.Pt(800, 800))
+	painter.StatefulOperationList{FigureOperations: []*painter.OperationFigure{{
+		Center: painter.RelativePoint{X: 0.5, Y: 0.5},
+		Blend:  painter.BlendXor,
+	}}}.Do(canvas)
+	assert.Equal(t, canvas.RGBAAt(300, 300), canvas.RGBAAt(400, 300))
+	assert.NotEqual(t, canvas.RGBAAt(0, 0), canvas.RGBAAt(400, 300))
+}
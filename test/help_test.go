@@ -0,0 +1,90 @@
+package test
+
+import (
+	"encoding/json"
+	stdcolor "image/color"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Help(t *testing.T) {
+	p := &lang.Parser{}
+
+	ops, err := p.Parse(strings.NewReader("help"))
+	assert.Nil(t, err)
+	assert.Equal(t, []painter.Operation{lang.Message(lang.DefaultRegistry.Help())}, ops)
+
+	ops, err = p.Parse(strings.NewReader("help figure"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(ops))
+	help := string(ops[0].(lang.Message))
+	assert.True(t, strings.HasPrefix(help, "figure x y [alpha] [blend]\n"))
+	assert.Contains(t, help, "x (float, [-1,1]): центр по горизонталі")
+
+	_, err = p.Parse(strings.NewReader("help circle"))
+	assert.EqualError(t, err, "Unknown command")
+}
+
+func TestHttpHandler_Help(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+	handler := lang.HttpHandler(&l, &p)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("help move\nwhite")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "move dx dy\n"))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"op": "help", "command": "reset"}]`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"output": ["reset\n    Очищує стан малюнку.\n"]}`, rec.Body.String())
+
+	l.StopAndWait()
+	assert.Equal(t, painter.OperationFill{Color: stdcolor.White}, p.State().BgOperation)
+}
+
+func TestCommandsHandler(t *testing.T) {
+	handler := lang.CommandsHandler(lang.DefaultRegistry)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/commands", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var cmds []lang.CommandInfo
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&cmds))
+	var names []string
+	for _, c := range cmds {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, lang.CommandNames(), names)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/commands/animate", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var animate lang.CommandInfo
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&animate))
+	assert.Equal(t, 4, len(animate.Subcommands))
+	move := animate.Subcommands[0]
+	assert.Equal(t, "move dx dy duration_ms [easing]", move.Usage)
+	assert.Equal(t, -1.0, *move.Args[0].Min)
+	assert.Equal(t, 1.0, *move.Args[0].Max)
+	assert.True(t, move.Args[2].Positive)
+	assert.Equal(t, "linear", move.Args[3].Default)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/commands/circle", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_ParseJSON(t *testing.T) {
+	t.Run("JSON commands produce the same operations as the text script", func(t *testing.T) {
+		textParser := &lang.Parser{}
+		textOps, err := textParser.Parse(strings.NewReader("green\nbgrect 0.1 0.2 0.3 0.4\nfigure 0.5 0.5\nmove -0.1 0.1\nupdate"))
+		assert.Nil(t, err)
+
+		jsonParser := &lang.Parser{}
+		jsonOps, err := jsonParser.ParseJSON(strings.NewReader(`[
+			{"op": "green"},
+			{"op": "bgrect", "x1": 0.1, "y1": 0.2, "x2": 0.3, "y2": 0.4},
+			{"op": "figure", "x": 0.5, "y": 0.5},
+			{"op": "move", "args": [-0.1, 0.1]},
+			{"op": "update"}
+		]`))
+		assert.Nil(t, err)
+
+		assert.Equal(t, len(textOps), len(jsonOps))
+		for idx := range textOps {
+			assert.IsType(t, textOps[idx], jsonOps[idx])
+		}
+		assert.Equal(t, textParser.State(), jsonParser.State())
+	})
+
+	testTable := []struct {
+		name string
+		cmd  string
+		err  string
+	}{
+		{name: "not an array", cmd: `{"op": "white"}`, err: "Invalid JSON: json: cannot unmarshal object into Go value of type []map[string]interface {}"},
+		{name: "missing op", cmd: `[{"x": 0.5}]`, err: "Command 0: Missing op"},
+		{name: "invalid op", cmd: `[{"op": "white green"}]`, err: "Command 0: Invalid op"},
+		{name: "unknown command", cmd: `[{"op": "white"}, {"op": "hello"}]`, err: "Command 1: Unknown command"},
+		{name: "missing field", cmd: `[{"op": "figure", "x": 0.5}]`, err: "Command 0: Missing field y"},
+		{name: "string instead of number", cmd: `[{"op": "figure", "x": "0.5", "y": 0.5}]`, err: "Command 0: Invalid argument at pos 0"},
+		{name: "unknown field", cmd: `[{"op": "figure", "x": 0.5, "y": 0.5, "z": 1, "color": 2}]`, err: "Command 0: Unknown fields: color, z"},
+		{name: "extra field for command without arguments", cmd: `[{"op": "white", "x": 0.5}]`, err: "Command 0: Unknown fields: x"},
+		{name: "out of range", cmd: `[{"op": "move", "dx": 0.5, "dy": 1.5}]`, err: "Command 0: Value at pos 1 is not in [-1,1] range"},
+		{name: "args with named fields", cmd: `[{"op": "move", "dx": 0.5, "args": [0.1, 0.1]}]`, err: "Command 0: Named arguments can't be combined with args"},
+		{name: "string is a single argument", cmd: `[{"op": "help", "command": "white; reset"}]`, err: "Command 0: Unknown command"},
+		{name: "wrong args count", cmd: `[{"op": "figure", "args": [0.1]}]`, err: "Command 0: Invalid argument count"},
+	}
+
+	for _, test := range testTable {
+		p := &lang.Parser{}
+		_, err := p.ParseJSON(strings.NewReader(test.cmd))
+		assert.EqualError(t, err, test.err, test.name)
+	}
+}
+
+func TestHttpHandler_JSON(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+	handler := lang.HttpHandler(&l, &p)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"op":"figure","x":0.5,"y":0.5},{"op":"update"}]`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`[{"op":"figure","x":5,"y":0.5}]`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"error": "Command 0: Value at pos 0 is not in [-1,1] range", "command": 0}`, rec.Body.String())
+
+	l.StopAndWait()
+	assert.Equal(t, 1, len(p.State().FigureOperations))
+	assert.NotNil(t, tr.LastTexture)
+}
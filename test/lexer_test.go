@@ -0,0 +1,138 @@
+package test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	statements, err := lang.Tokenize("figure 0.5 y=-0.1 # centre\n\n  ; white;update\nafter 1s \"move 0.1 0; update\" x=\"a \\\"b\\\"\"")
+	assert.Nil(t, err)
+
+	var got [][]string
+	for _, tokens := range statements {
+		var words []string
+		for _, tok := range tokens {
+			word := tok.Value
+			if tok.Key != "" {
+				word = tok.Key + "=" + word
+			}
+			words = append(words, word)
+		}
+		got = append(got, words)
+	}
+	assert.Equal(t, [][]string{
+		{"figure", "0.5", "y=-0.1"},
+		{"white"},
+		{"update"},
+		{"after", "1s", "move 0.1 0; update", `x=a "b"`},
+	}, got)
+
+	last := statements[3]
+	assert.Equal(t, 4, last[0].Line)
+	assert.Equal(t, 10, last[2].Column)
+	assert.True(t, last[2].Quoted)
+
+	testTable := []struct {
+		script string
+		err    string
+		col    int
+	}{
+		{script: `after 1s "update`, err: "Unterminated string", col: 10},
+		{script: `figure "a\q"`, err: `Invalid escape sequence \q`, col: 10},
+		{script: `figure "a"b`, err: "Unexpected character after string", col: 11},
+	}
+	for _, test := range testTable {
+		_, err := lang.Tokenize(test.script)
+		var parseErr *lang.ParseError
+		if assert.True(t, errors.As(err, &parseErr), test.script) {
+			assert.Equal(t, test.err, parseErr.Error())
+			assert.Equal(t, test.col, parseErr.Column, test.script)
+		}
+	}
+}
+
+func TestParser_Syntax(t *testing.T) {
+	t.Run("Comments, blank lines and separators", func(t *testing.T) {
+		p := &lang.Parser{}
+		ops, err := p.Parse(strings.NewReader("# scene\n\n   \nwhite; figure 0.1 0.1 # first\nfigure 0.2 0.2;update;"))
+		assert.Nil(t, err)
+		// Зміни стану перед "update" надсилаються однією операцією.
+		assert.Equal(t, 2, len(ops))
+		assert.Equal(t, painter.UpdateOp, ops[1])
+		assert.Equal(t, 2, len(p.State().FigureOperations))
+	})
+
+	t.Run("Named arguments", func(t *testing.T) {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader("figure y=0.5 x=-0.2\nbgrect 0.1 0.2 y2=0.4 x2=0.3"))
+		assert.Nil(t, err)
+		st := p.State()
+		assert.Equal(t, painter.RelativePoint{X: -0.2, Y: 0.5}, st.FigureOperations[0].Center)
+		assert.Equal(t, painter.OperationBGRect{
+			Min: painter.RelativePoint{X: 0.1, Y: 0.2},
+			Max: painter.RelativePoint{X: 0.3, Y: 0.4},
+		}, st.BgRectOperations[0])
+	})
+
+	t.Run("Quoted strings", func(t *testing.T) {
+		p := &lang.Parser{}
+		ops, err := p.Parse(strings.NewReader(`help "figure"`))
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(string(ops[0].(lang.Message)), "figure x y"))
+	})
+
+	testTable := []struct {
+		name      string
+		script    string
+		err       string
+		line, col int
+	}{
+		{name: "unknown command", script: "white\n  hello 1", err: "Unknown command", line: 2, col: 3},
+		{name: "invalid value", script: "figure 0.1 j", err: "Invalid argument at pos 1", line: 1, col: 12},
+		{name: "invalid named value", script: "figure y=0.1 x=3", err: "Value at pos 0 is not in [-1,1] range", line: 1, col: 14},
+		{name: "argument count", script: "white; move 0.1", err: "Invalid argument count", line: 1, col: 13},
+		{name: "unknown argument", script: "figure 0.1 0.1 z=1", err: "Unknown argument z", line: 1, col: 16},
+		{name: "duplicate argument", script: "figure 0.1 x=0.2", err: "Duplicate argument x", line: 1, col: 12},
+		{name: "positional after named", script: "figure x=0.2 0.1", err: "Positional argument after named ones", line: 1, col: 14},
+		{name: "unknown subcommand", script: "animate spin 1", err: "Unknown animation", line: 1, col: 9},
+		{name: "unterminated string", script: "update\nhelp \"figure", err: "Unterminated string", line: 2, col: 6},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			p := &lang.Parser{}
+			_, err := p.Parse(strings.NewReader(test.script))
+			var parseErr *lang.ParseError
+			if assert.True(t, errors.As(err, &parseErr)) {
+				assert.Equal(t, test.err, parseErr.Error())
+				assert.Equal(t, test.line, parseErr.Line)
+				assert.Equal(t, test.col, parseErr.Column)
+			}
+		})
+	}
+}
+
+func TestHttpHandler_ErrorPosition(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+	handler := lang.HttpHandler(&l, &p)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("white\nfigure 0.1 2")))
+	l.StopAndWait()
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"error": "Value at pos 1 is not in [-1,1] range", "line": 2, "column": 12}`, rec.Body.String())
+}
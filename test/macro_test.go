@@ -0,0 +1,174 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/client"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+const framedScene = `def frame(x, y) {
+  bgrect ($x - 0.2) ($y - 0.2) ($x + 0.2) ($y + 0.2)
+  figure $x $y
+}
+def scene { green; frame 0 0 }`
+
+func TestParser_Macros(t *testing.T) {
+	p := &lang.Parser{}
+	ops, err := p.Parse(strings.NewReader(framedScene))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(ops))
+	assert.Equal(t, []lang.Macro{
+		{Name: "frame", Params: []string{"x", "y"}, Body: "bgrect ($x - 0.2) ($y - 0.2) ($x + 0.2) ($y + 0.2)\n  figure $x $y"},
+		{Name: "scene", Params: []string{}, Body: "green; frame 0 0"},
+	}, p.Macros())
+
+	// Макроси зберігаються між запитами, а їх аргументи перевіряються як аргументи команд.
+	let := "let x = 0.75\n"
+	ops, err = p.Parse(strings.NewReader(let + "scene\nframe y=0.5 x=($x - 0.25)\nupdate"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(ops))
+	assert.Equal(t, []painter.RelativePoint{{X: 0, Y: 0}, {X: 0.5, Y: 0.5}}, figureCenters(p))
+	// Кожен виклик frame додає свій прямокутник.
+	rects := p.State().BgRectOperations
+	assert.Equal(t, 2, len(rects))
+	assert.Equal(t, painter.OperationBGRect{
+		Min: painter.RelativePoint{X: 0.3, Y: 0.3},
+		Max: painter.RelativePoint{X: 0.7, Y: 0.7},
+	}, roundRect(rects[1]))
+	// Параметри макросу не змінюють змінні з тими ж назвами.
+	assert.Equal(t, 0.75, p.Vars()["x"])
+
+	testTable := []struct {
+		name      string
+		script    string
+		err       string
+		line, col int
+	}{
+		{name: "argument count", script: "update\nframe 0.1", err: "Invalid argument count", line: 2, col: 7},
+		{name: "error inside macro", script: "frame 0.9 0", err: "In frame: Value at pos 2 is not in [-1,1] range", line: 1, col: 1},
+		{name: "builtin name", script: "def move(a) { update }", err: "Command move already exists", line: 1, col: 5},
+		{name: "invalid parameter", script: "def f(a, 1) { update }", err: `Invalid parameter name "1"`, line: 1, col: 5},
+		{name: "recursion", script: "def loop { loop }\nloop", err: "In loop: " + strings.Repeat("In loop: ", 31) + "Macro calls are nested deeper than 32", line: 2, col: 1},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := p.Parse(strings.NewReader(test.script))
+			var parseErr *lang.ParseError
+			if assert.True(t, errors.As(err, &parseErr)) {
+				assert.Equal(t, test.err, parseErr.Error())
+				assert.Equal(t, test.line, parseErr.Line)
+				assert.Equal(t, test.col, parseErr.Column)
+			}
+		})
+	}
+	// Макрос з помилкового скрипту не зберігається.
+	assert.Equal(t, 2, len(p.Macros()))
+
+	assert.True(t, p.DeleteMacro("scene"))
+	assert.False(t, p.DeleteMacro("scene"))
+	_, err = p.Parse(strings.NewReader("scene"))
+	assert.EqualError(t, err, "Unknown command")
+}
+
+func TestParser_MacroSessions(t *testing.T) {
+	p := &lang.Parser{Scheduler: &lang.Scheduler{}}
+	alice, bob := p.Session("alice"), p.Session("bob")
+	_, err := alice.Parse(strings.NewReader("def mark { figure 0.5 0.5 }"))
+	assert.Nil(t, err)
+	_, err = bob.ParseJSON(strings.NewReader(`[{"op": "mark"}]`))
+	assert.EqualError(t, err, "Command 0: Unknown command")
+	_, err = p.Parse(strings.NewReader("mark"))
+	assert.EqualError(t, err, "Unknown command")
+
+	// Однакові назви в різних сесіях не конфліктують, а стан малюнку спільний.
+	_, err = bob.Parse(strings.NewReader("def mark { figure -0.5 -0.5 }\nmark"))
+	assert.Nil(t, err)
+	_, err = alice.Parse(strings.NewReader("mark"))
+	assert.Nil(t, err)
+	assert.Equal(t, []painter.RelativePoint{{X: -0.5, Y: -0.5}, {X: 0.5, Y: 0.5}}, figureCenters(p))
+	assert.Equal(t, "figure 0.5 0.5", alice.Macros()[0].Body)
+	assert.Equal(t, "figure -0.5 -0.5", bob.Macros()[0].Body)
+	assert.Equal(t, 0, len(p.Macros()))
+
+	// Заплановані команди виконуються у сесії, де їх запланували.
+	ops, err := alice.Parse(strings.NewReader("after 1s mark"))
+	assert.Nil(t, err)
+	if assert.Equal(t, 1, len(ops)) {
+		assert.Equal(t, "alice", ops[0].(lang.ScheduleOp).Session)
+	}
+
+	alice.DeleteMacros()
+	assert.Equal(t, 0, len(alice.Macros()))
+	assert.Equal(t, 1, len(bob.Macros()))
+}
+
+// roundRect округлює координати прямокутника, щоб порівнювати результати обчислень з рухомою комою.
+func roundRect(rect painter.OperationBGRect) painter.OperationBGRect {
+	round := func(v float64) float64 { return float64(int(v*1e6+0.5)) / 1e6 }
+	rect.Min = painter.RelativePoint{X: round(rect.Min.X), Y: round(rect.Min.Y)}
+	rect.Max = painter.RelativePoint{X: round(rect.Max.X), Y: round(rect.Max.Y)}
+	return rect
+}
+
+func TestMacrosHandler(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+	defer l.StopAndWait()
+
+	mux := http.NewServeMux()
+	mux.Handle("/macros", lang.MacrosHandler(&p))
+	mux.Handle("/macros/", lang.MacrosHandler(&p))
+	mux.Handle("/", lang.HttpHandler(&l, &p))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	c := client.New(server.URL)
+	_, err := c.SendScript(ctx, framedScene)
+	assert.Nil(t, err)
+	assert.Nil(t, c.Send(ctx, client.Command{Op: "frame", Named: map[string]any{"x": 0.1, "y": 0.2}}))
+
+	macros, err := c.Macros(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(macros))
+	assert.Equal(t, "frame", macros[0].Name)
+
+	resp, err := http.Get(server.URL + "/macros/scene")
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Nil(t, c.DeleteMacro(ctx, "scene"))
+	var painterErr *client.Error
+	err = c.DeleteMacro(ctx, "scene")
+	assert.True(t, errors.As(err, &painterErr))
+	assert.Equal(t, http.StatusNotFound, painterErr.StatusCode)
+
+	// Клієнт з іншою сесією не бачить макросів першого.
+	other := client.New(server.URL)
+	other.Session = "other"
+	macros, err = other.Macros(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(macros))
+	err = other.Send(ctx, client.Command{Op: "frame", Args: []any{0.1, 0.2}})
+	assert.True(t, errors.As(err, &painterErr))
+	assert.Equal(t, "Command 0: Unknown command", painterErr.Message)
+
+	assert.Nil(t, c.DeleteMacros(ctx))
+	macros, err = c.Macros(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(macros))
+}
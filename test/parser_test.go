@@ -77,6 +77,21 @@ func TestParser_Input(t *testing.T) {
 			cmd:  "reset\n figure j -0.9\n green\n update",
 			err:  "Invalid argument at pos 0",
 		},
+		{
+			name: "NaN coordinate",
+			cmd:  "figure NaN 0.5",
+			err:  "Invalid argument at pos 0",
+		},
+		{
+			name: "infinite coordinate",
+			cmd:  "bgrect 0.1 0.1 0.5 -Inf",
+			err:  "Invalid argument at pos 3",
+		},
+		{
+			name: "infinite positive value",
+			cmd:  "figure 0.5 0.5\n scale +Inf",
+			err:  "Invalid argument at pos 0",
+		},
 	}
 
 	for _, test := range testTable {
@@ -101,7 +116,6 @@ func TestParser_Operation(t *testing.T) {
 			name: "Simple consequent commands with state and without",
 			cmd:  "white\ngreen\nupdate",
 			ops: []painter.Operation{
-				painter.StatefulOperationList{},
 				painter.StatefulOperationList{},
 				painter.UpdateOp,
 			},
@@ -112,22 +126,17 @@ func TestParser_Operation(t *testing.T) {
 			cmd:  "white\nfigure 0.5 0.5\nfigure -0.1 0.933",
 			ops: []painter.Operation{
 				painter.StatefulOperationList{},
-				painter.StatefulOperationList{},
-				painter.StatefulOperationList{},
 			},
 			figures: []*painter.OperationFigure{
 				{Center: painter.RelativePoint{X: 0.5, Y: 0.5}},
 				{Center: painter.RelativePoint{X: -0.1, Y: 0.933}},
 			},
-			checkIdx: 2,
+			checkIdx: 0,
 		},
 		{
 			name: "Figures have correct position after multiple move operations",
 			cmd:  "figure 0.5 0.5\nfigure 0.4 0.35\nmove 0.2 0.2\nmove -0.1 0.1\nupdate",
 			ops: []painter.Operation{
-				painter.StatefulOperationList{},
-				painter.StatefulOperationList{},
-				painter.StatefulOperationList{},
 				painter.StatefulOperationList{},
 				painter.UpdateOp,
 			},
@@ -135,7 +144,7 @@ func TestParser_Operation(t *testing.T) {
 				{Center: painter.RelativePoint{X: 0.6, Y: 0.8}},
 				{Center: painter.RelativePoint{X: 0.5, Y: 0.65}},
 			},
-			checkIdx: 2,
+			checkIdx: 0,
 		},
 	}
 	delta := 0.00001
@@ -162,3 +171,17 @@ func TestParser_Operation(t *testing.T) {
 		}
 	}
 }
+
+func TestParser_Rollback(t *testing.T) {
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("green\nfigure 0.5 0.5"))
+	assert.Nil(t, err)
+
+	_, err = p.Parse(strings.NewReader("move 0.1 0.1\nreset\nfigure 2 2"))
+	assert.NotNil(t, err)
+
+	st := p.State()
+	assert.NotNil(t, st.BgOperation)
+	assert.Equal(t, 1, len(st.FigureOperations))
+	assert.InDelta(t, 0.5, st.FigureOperations[0].Center.X, 0.00001)
+}
@@ -0,0 +1,94 @@
+package test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParser_Patterns(t *testing.T) {
+	type pixel struct {
+		x, y  int
+		color color.RGBA
+	}
+	var (
+		red    = color.RGBA{R: 0xff, A: 0xff}
+		blue   = color.RGBA{B: 0xff, A: 0xff}
+		orange = color.RGBA{R: 0xff, G: 0x80, A: 0xff}
+	)
+	testTable := []struct {
+		name   string
+		script string
+		pixels []pixel
+	}{
+		{
+			name:   "linear gradient",
+			script: "gradient linear 0 0 1 0 red blue",
+			pixels: []pixel{{0, 0, red}, {199, 199, blue}, {100, 50, color.RGBA{R: 0x7f, B: 0x80, A: 0xff}}},
+		},
+		{
+			name:   "radial gradient",
+			script: `gradient radial 0.5 0.5 0.25 "#ff8000" blue`,
+			pixels: []pixel{{100, 100, color.RGBA{R: 0xfc, G: 0x7e, B: 0x3, A: 0xff}}, {0, 0, blue}, {100, 160, blue}},
+		},
+		{
+			name:   "checkerboard",
+			script: "pattern checkerboard 0.25 red rgb(255, 128, 0)",
+			pixels: []pixel{{10, 10, red}, {60, 10, orange}, {60, 60, red}, {199, 0, orange}},
+		},
+		{
+			name:   "stripes",
+			script: "pattern stripes 0.25 red blue angle=90",
+			pixels: []pixel{{10, 10, red}, {10, 60, blue}, {160, 10, red}},
+		},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			p := &lang.Parser{}
+			_, err := p.Parse(strings.NewReader(test.script))
+			assert.Nil(t, err)
+
+			tx := newImageTexture(image.Pt(200, 200))
+			p.State().Do(tx)
+			for _, px := range test.pixels {
+				assert.Equal(t, px.color, tx.RGBAAt(px.x, px.y), "pixel %d %d", px.x, px.y)
+			}
+		})
+	}
+
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("pattern checkerboard 0.1 red purple"))
+	assert.EqualError(t, err, "Invalid color at pos 2")
+	_, err = p.Parse(strings.NewReader("gradient radial 0.5 0.5 0 red blue"))
+	assert.EqualError(t, err, "Value at pos 2 is not positive")
+	_, err = p.Parse(strings.NewReader("pattern dots 0.1 red blue"))
+	assert.EqualError(t, err, "Unknown pattern")
+}
+
+func TestOperationPattern(t *testing.T) {
+	t.Run("Texture without uploads is filled with a single color", func(t *testing.T) {
+		tx := new(mockTexture)
+		painter.PatternFill(painter.Checkerboard{Cell: 0.1, A: color.White, B: color.Black}).Do(tx)
+		assert.Equal(t, 1, tx.FillCnt)
+		assert.Equal(t, 0, tx.UploadCnt)
+	})
+
+	t.Run("SVG export", func(t *testing.T) {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader("gradient linear 0 0 0 1 white rgba(0, 0, 0, 0.5)\nfigure 0.5 0.5"))
+		assert.Nil(t, err)
+
+		var buf bytes.Buffer
+		assert.Nil(t, painter.ExportSVG(&buf, p.State()))
+		out := buf.String()
+		assert.Contains(t, out, `<linearGradient id="background" gradientUnits="userSpaceOnUse" x1="0" y1="0" x2="0" y2="800">`+
+			`<stop offset="0" stop-color="rgb(255,255,255)"/><stop offset="1" stop-color="rgb(0,0,0)" stop-opacity="0.502"/>`)
+		assert.Contains(t, out, `<rect x="0" y="0" width="800" height="800" fill="url(#background)"/>`)
+	})
+}
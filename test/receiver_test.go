@@ -0,0 +1,125 @@
+package test
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/shiny/screen"
+)
+
+func TestReceivers(t *testing.T) {
+	var (
+		l      painter.Loop
+		first  testReceiver
+		second testReceiver
+	)
+	// Порожні отримувачі пропускаються.
+	l.Receiver = painter.Receivers{&first, nil, &second}
+	l.Start(mockScreen{})
+	l.Post(mockUpdateOperation{})
+	l.StopAndWait()
+
+	assert.NotNil(t, first.LastTexture)
+	assert.Same(t, first.LastTexture, second.LastTexture)
+}
+
+// opaqueScreen створює текстури, пікселі яких прочитати не можна, як і текстури справжнього екрана.
+type opaqueScreen struct {
+	mockScreen
+}
+
+func (opaqueScreen) NewTexture(size image.Point) (screen.Texture, error) {
+	return &opaqueTexture{size: size}, nil
+}
+
+type opaqueTexture struct {
+	mockTexture
+	size image.Point
+}
+
+func (t *opaqueTexture) Size() image.Point       { return t.size }
+func (t *opaqueTexture) Bounds() image.Rectangle { return image.Rectangle{Max: t.size} }
+
+// decodeFrame читає записаний кадр і повертає колір його пікселя (x, y).
+func decodeFrame(t *testing.T, path string, x, y int) color.Color {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	assert.Nil(t, err)
+	return color.RGBAModel.Convert(img.At(x, y))
+}
+
+func TestPNGRecorder(t *testing.T) {
+	t.Run("Frames are drawn from their own state", func(t *testing.T) {
+		var (
+			l painter.Loop
+			p lang.Parser
+		)
+		dir := t.TempDir()
+		recorder := &painter.PNGRecorder{Path: filepath.Join(dir, "frame-%02d.png")}
+		l.Receiver = painter.Receivers{recorder}
+		l.Start(opaqueScreen{})
+
+		// Цикл подій чекає, поки стан малюнку не зміниться після обох кадрів.
+		release := make(chan struct{})
+		l.Post(painter.OperationFunc(func(screen.Texture) { <-release }))
+		for _, script := range []string{"green\nupdate", "white\nupdate", "reset"} {
+			ops, err := p.Parse(strings.NewReader(script))
+			assert.Nil(t, err)
+			for _, op := range ops {
+				l.Post(op)
+			}
+		}
+		close(release)
+		l.StopAndWait()
+		recorder.Close()
+
+		assert.Equal(t, color.RGBA{G: 0xff, A: 0xff}, decodeFrame(t, filepath.Join(dir, "frame-00.png"), 150, 50))
+		assert.Equal(t, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+			decodeFrame(t, filepath.Join(dir, "frame-01.png"), 150, 50))
+		_, err := os.Stat(filepath.Join(dir, "frame-02.png"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("Readable textures are copied", func(t *testing.T) {
+		dir := t.TempDir()
+		recorder := &painter.PNGRecorder{Path: filepath.Join(dir, "frame-%02d.png")}
+		tx := newImageTexture(image.Pt(200, 100))
+		painter.OperationFill{Color: color.RGBA{R: 0xff, A: 0xff}}.Do(tx)
+		recorder.Update(tx)
+		// Зміни текстури після Update не потрапляють у записаний кадр.
+		painter.OperationFill{Color: color.White}.Do(tx)
+		recorder.Close()
+		assert.Equal(t, color.RGBA{R: 0xff, A: 0xff}, decodeFrame(t, filepath.Join(dir, "frame-00.png"), 150, 50))
+	})
+
+	t.Run("Frames are dropped when the queue is full", func(t *testing.T) {
+		dir := t.TempDir()
+		recorder := &painter.PNGRecorder{Path: filepath.Join(dir, "frame-%03d.png"), Queue: 1}
+		tx := &opaqueTexture{size: image.Pt(400, 400)}
+		for i := 0; i < 100; i++ {
+			recorder.UpdateFrame(tx, painter.StatefulOperationList{})
+		}
+		recorder.Close()
+
+		// Записані кадри нумеруються без пропусків.
+		files, err := os.ReadDir(dir)
+		assert.Nil(t, err)
+		assert.Equal(t, 100, len(files)+recorder.Dropped())
+		for idx, file := range files {
+			assert.Equal(t, fmt.Sprintf("frame-%03d.png", idx), file.Name())
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+// counterTweaker рахує, скільки разів його застосували, для перевірки власних команд.
+type counterTweaker struct {
+	count *int
+	step  int
+}
+
+func (t counterTweaker) SetState(sol *painter.StatefulOperationList) {
+	*t.count += t.step
+}
+
+func TestRegistry(t *testing.T) {
+	count := 0
+	r := lang.NewRegistry()
+	err := r.Register(lang.Command{
+		Name:        "count",
+		Description: "Counts.",
+		Args: []lang.Arg{
+			{Name: "step", Type: lang.Int, Bounded: true, Min: 1, Max: 10, Optional: true, Default: 1},
+		},
+		Tweak: func(args lang.Args) painter.StateTweaker {
+			return counterTweaker{count: &count, step: args.Int(0)}
+		},
+	})
+	assert.Nil(t, err)
+
+	t.Run("Custom commands are parsed with defaults", func(t *testing.T) {
+		p := &lang.Parser{Registry: r}
+		ops, err := p.Parse(strings.NewReader("count\ncount 5"))
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(ops))
+		assert.Equal(t, 6, count)
+
+		_, err = p.Parse(strings.NewReader("count 11"))
+		assert.EqualError(t, err, "Value at pos 0 is not in [1,10] range")
+		_, err = p.Parse(strings.NewReader("count 1 2"))
+		assert.EqualError(t, err, "Invalid argument count")
+		_, err = p.Parse(strings.NewReader("white"))
+		assert.EqualError(t, err, "Unknown command")
+	})
+
+	t.Run("Integer expressions are parsed like literals", func(t *testing.T) {
+		p := &lang.Parser{Animator: &painter.Animator{}}
+		for _, script := range []string{
+			"animate move 0.1 0.1 1000000",
+			"let d = 1000\nanimate move 0.1 0.1 ($d*1000)",
+			"animate move 0.1 0.1 (1e21 / 1e15)",
+		} {
+			ops, err := p.Parse(strings.NewReader(script))
+			if assert.Nil(t, err, script) && assert.Equal(t, 1, len(ops), script) {
+				assert.Equal(t, 1000*time.Second, ops[0].(painter.AnimateOp).Animation.Duration, script)
+			}
+		}
+		// Занадто велике або дробове значення відхиляється так само, як і число в скрипті.
+		for _, arg := range []string{"1000000000000000000000", "(1e21)", "1.5", "(3/2)"} {
+			_, err := p.Parse(strings.NewReader("animate move 0.1 0.1 " + arg))
+			assert.EqualError(t, err, "Invalid argument at pos 2", arg)
+		}
+	})
+
+	t.Run("Named JSON fields are taken from the schema", func(t *testing.T) {
+		p := &lang.Parser{Registry: r}
+		_, err := p.ParseJSON(strings.NewReader(`[{"op": "count", "step": 3}, {"op": "count"}]`))
+		assert.Nil(t, err)
+		assert.Equal(t, 10, count)
+	})
+
+	t.Run("Invalid definitions are rejected", func(t *testing.T) {
+		assert.EqualError(t, r.Register(lang.Command{Name: "count", Op: nil, Tweak: func(lang.Args) painter.StateTweaker { return nil }}),
+			"command count is already registered")
+		assert.EqualError(t, r.Register(lang.Command{Name: "nothing"}),
+			"command nothing: exactly one of Tweak and Op must be set")
+		assert.EqualError(t, r.Register(lang.Command{
+			Name:  "bad",
+			Args:  []lang.Arg{{Name: "a", Optional: true}, {Name: "b"}},
+			Tweak: func(lang.Args) painter.StateTweaker { return nil },
+		}), "command bad: required argument b follows an optional one")
+		assert.EqualError(t, r.Register(lang.Command{Name: "two words"}), `invalid command name "two words"`)
+	})
+
+	t.Run("Help is generated from definitions", func(t *testing.T) {
+		assert.Equal(t, "count [step]\n    Counts.\n    step (int, [1,10], default 1)\n", r.Help())
+
+		help := lang.DefaultRegistry.Help()
+		assert.Contains(t, help, "figure x y [alpha] [blend]\n")
+		assert.Contains(t, help, "    x (float, [-1,1]): ")
+		assert.Contains(t, help, "animate move dx dy duration_ms [easing]\n")
+		assert.Contains(t, help, "animate stop\n")
+		assert.Contains(t, help, "every interval command...\n")
+	})
+
+	assert.Equal(t, []string{"white", "green", "gradient", "pattern", "update", "bgrect", "figure", "move", "rotate", "scale", "reset", "undo", "animate", "help", "coords", "after", "every"},
+		lang.CommandNames())
+}
@@ -0,0 +1,127 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/png"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/MytsV/architecture-lab-3/painter/rpc"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestRPCServer(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	server := &rpc.Server{Loop: &l, Parser: &p}
+	l.Receiver = server.Notify(&tr)
+	l.Start(mockScreen{})
+
+	ln := bufconn.Listen(1024 * 1024)
+	gs := grpc.NewServer()
+	rpc.RegisterPainterServer(gs, server)
+	go gs.Serve(ln)
+	defer gs.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	defer conn.Close()
+	client := rpc.NewPainterClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Subscribe(ctx, &rpc.SubscribeRequest{})
+	assert.Nil(t, err)
+
+	_, err = client.Fill(ctx, &rpc.FillRequest{Color: &rpc.Color{G: 0xff}})
+	assert.Nil(t, err)
+	_, err = client.Figure(ctx, &rpc.FigureRequest{Center: &rpc.Point{X: 0.5, Y: 0.5}})
+	assert.Nil(t, err)
+	_, err = client.Move(ctx, &rpc.MoveRequest{Offset: &rpc.Point{X: 0.1, Y: -0.1}})
+	assert.Nil(t, err)
+
+	_, err = client.Move(ctx, &rpc.MoveRequest{Offset: &rpc.Point{X: 0.1, Y: -3}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "Value at pos 1 is not in [-1,1] range", status.Convert(err).Message())
+	_, err = client.Fill(ctx, &rpc.FillRequest{Color: &rpc.Color{R: 256}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	figures := p.State().FigureOperations
+	assert.Equal(t, 1, len(figures))
+	assert.InDelta(t, 0.6, figures[0].Center.X, 0.00001)
+	assert.InDelta(t, 0.4, figures[0].Center.Y, 0.00001)
+
+	// Кадр ще не намальований, тому GetFrame не бачить змін до "update".
+	frame, err := client.GetFrame(ctx, &rpc.GetFrameRequest{})
+	assert.Nil(t, err)
+	assert.Equal(t, "image/png", frame.ContentType)
+	img, err := png.Decode(bytes.NewReader(frame.Data))
+	assert.Nil(t, err)
+	assert.Equal(t, painter.TextureSize(), img.Bounds().Size())
+	assert.NotEqual(t, color.RGBA{G: 0xff, A: 0xff}, color.RGBAModel.Convert(img.At(10, 10)))
+
+	// Підписка може зареєструватися на сервері пізніше за перше оновлення, тому оновлюємо, поки не отримаємо кадр.
+	received := make(chan *rpc.Frame)
+	go func() {
+		frame, err := stream.Recv()
+		if err == nil {
+			received <- frame
+		}
+	}()
+	var streamed *rpc.Frame
+	for streamed == nil {
+		_, err = client.Update(ctx, &rpc.UpdateRequest{})
+		assert.Nil(t, err)
+		select {
+		case streamed = <-received:
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("no frame received from Subscribe")
+		}
+	}
+	assert.Equal(t, "image/png", streamed.ContentType)
+	img, err = png.Decode(bytes.NewReader(streamed.Data))
+	assert.Nil(t, err)
+	assert.Equal(t, color.RGBA{G: 0xff, A: 0xff}, color.RGBAModel.Convert(img.At(10, 10)))
+
+	// Зміни без "update" не потрапляють у кадр, навіть якщо вже є у стані Parser.
+	_, err = client.Fill(ctx, &rpc.FillRequest{Color: &rpc.Color{R: 0xff}})
+	assert.Nil(t, err)
+	frame, err = client.GetFrame(ctx, &rpc.GetFrameRequest{})
+	assert.Nil(t, err)
+	img, err = png.Decode(bytes.NewReader(frame.Data))
+	assert.Nil(t, err)
+	assert.Equal(t, color.RGBA{G: 0xff, A: 0xff}, color.RGBAModel.Convert(img.At(10, 10)))
+
+	// Кожен виклик зберігається в історії, як окремий скрипт.
+	_, err = p.Undo()
+	assert.Nil(t, err)
+	assert.Equal(t, painter.OperationFill{Color: color.NRGBA{G: 0xff, A: 0xff}}, p.State().BgOperation)
+	_, err = p.Undo()
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.5, p.State().FigureOperations[0].Center.X, 0.00001)
+
+	_, err = client.Reset(ctx, &rpc.ResetRequest{})
+	assert.Nil(t, err)
+	l.StopAndWait()
+	assert.Empty(t, p.State().FigureOperations)
+	assert.NotNil(t, tr.LastTexture)
+}
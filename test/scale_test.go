@@ -0,0 +1,76 @@
+package test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScaleMode_Viewport(t *testing.T) {
+	texture := image.Pt(800, 800)
+	testTable := []struct {
+		name     string
+		mode     ui.ScaleMode
+		window   image.Point
+		viewport image.Rectangle
+	}{
+		{name: "default stretches", window: image.Pt(1000, 500), viewport: image.Rect(0, 0, 1000, 500)},
+		{name: "stretch", mode: ui.ScaleStretch, window: image.Pt(1000, 500), viewport: image.Rect(0, 0, 1000, 500)},
+		{name: "fit wide window", mode: ui.ScaleFit, window: image.Pt(1000, 500), viewport: image.Rect(250, 0, 750, 500)},
+		{name: "fit tall window", mode: ui.ScaleFit, window: image.Pt(400, 600), viewport: image.Rect(0, 100, 400, 500)},
+		{name: "fill crops", mode: ui.ScaleFill, window: image.Pt(1000, 500), viewport: image.Rect(0, -250, 1000, 750)},
+		{name: "integer", mode: ui.ScaleInteger, window: image.Pt(1700, 2000), viewport: image.Rect(50, 200, 1650, 1800)},
+		{name: "integer below 1x fits", mode: ui.ScaleInteger, window: image.Pt(400, 600), viewport: image.Rect(0, 100, 400, 500)},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.viewport, test.mode.Viewport(test.window, texture))
+		})
+	}
+}
+
+func TestScaleMode_ToTexture(t *testing.T) {
+	texture := image.Pt(800, 800)
+	testTable := []struct {
+		name   string
+		mode   ui.ScaleMode
+		window image.Point
+		x, y   float64
+		at     painter.RelativePoint
+	}{
+		{name: "stretch", mode: ui.ScaleStretch, window: image.Pt(1000, 500), x: 250, y: 250,
+			at: painter.RelativePoint{X: 0.25, Y: 0.5}},
+		{name: "fit", mode: ui.ScaleFit, window: image.Pt(1000, 500), x: 375, y: 125,
+			at: painter.RelativePoint{X: 0.25, Y: 0.25}},
+		{name: "fit letterbox", mode: ui.ScaleFit, window: image.Pt(1000, 500), x: 100, y: 250,
+			at: painter.RelativePoint{X: -0.3, Y: 0.5}},
+		{name: "fit letterbox right", mode: ui.ScaleFit, window: image.Pt(1000, 500), x: 900, y: 250,
+			at: painter.RelativePoint{X: 1.3, Y: 0.5}},
+		{name: "fill", mode: ui.ScaleFill, window: image.Pt(1000, 500), x: 500, y: 0,
+			at: painter.RelativePoint{X: 0.5, Y: 0.25}},
+		{name: "integer", mode: ui.ScaleInteger, window: image.Pt(1700, 2000), x: 450, y: 600,
+			at: painter.RelativePoint{X: 0.25, Y: 0.25}},
+		{name: "integer letterbox", mode: ui.ScaleInteger, window: image.Pt(1700, 2000), x: 10, y: 1900,
+			at: painter.RelativePoint{X: -0.025, Y: 1.0625}},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			at := test.mode.ToTexture(test.window, texture, test.x, test.y)
+			assert.InDelta(t, test.at.X, at.X, 1e-9)
+			assert.InDelta(t, test.at.Y, at.Y, 1e-9)
+		})
+	}
+}
+
+func TestParseScaleMode(t *testing.T) {
+	for _, m := range ui.ScaleModes {
+		parsed, err := ui.ParseScaleMode(string(m))
+		assert.Nil(t, err)
+		assert.Equal(t, m, parsed)
+	}
+	_, err := ui.ParseScaleMode("zoom")
+	assert.EqualError(t, err, `unknown scale mode "zoom", expected one of [stretch fit fill integer]`)
+}
@@ -0,0 +1,150 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func newScheduledParser() (*painter.Loop, *lang.Parser, *lang.Scheduler) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+	s := &lang.Scheduler{Loop: &l, Parser: &p}
+	p.Scheduler = s
+	return &l, &p, s
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("Delayed command is executed once", func(t *testing.T) {
+		l, p, s := newScheduledParser()
+		runScript(t, l, p, "after 5ms figure 0.5 0.5")
+		assert.Equal(t, 1, len(s.Jobs()))
+		assert.Empty(t, p.State().FigureOperations)
+
+		assert.Eventually(t, func() bool { return len(s.Jobs()) == 0 }, time.Second, time.Millisecond)
+		l.StopAndWait()
+		assert.Equal(t, 1, len(p.State().FigureOperations))
+	})
+
+	t.Run("Periodic command is repeated until cancelled", func(t *testing.T) {
+		l, p, s := newScheduledParser()
+		runScript(t, l, p, "figure 0 0\nevery 2ms move 0.01 0")
+		assert.Eventually(t, func() bool {
+			jobs := s.Jobs()
+			return len(jobs) == 1 && jobs[0].Runs >= 3
+		}, time.Second, time.Millisecond)
+
+		jobs := s.Jobs()
+		assert.Equal(t, "move 0.01 0", jobs[0].Command)
+		assert.True(t, jobs[0].Repeat)
+		assert.True(t, s.Cancel(jobs[0].ID))
+		assert.False(t, s.Cancel(jobs[0].ID))
+		l.StopAndWait()
+
+		assert.Greater(t, p.State().FigureOperations[0].Center.X, 0.02)
+	})
+
+	t.Run("Command runs in the coordinate system it was scheduled in", func(t *testing.T) {
+		l, p, s := newScheduledParser()
+		runScript(t, l, p, "coords pixel\nafter 5ms figure 400 200\ncoords relative")
+		assert.Eventually(t, func() bool { return len(s.Jobs()) == 0 }, time.Second, time.Millisecond)
+		l.StopAndWait()
+
+		figures := p.State().FigureOperations
+		if assert.Equal(t, 1, len(figures)) {
+			assert.InDelta(t, 0.5, figures[0].Center.X, 1e-9)
+			assert.InDelta(t, 0.25, figures[0].Center.Y, 1e-9)
+		}
+		assert.Equal(t, lang.RelativeCoords, p.Coords())
+	})
+
+	t.Run("Command keeps the session macros after they are deleted", func(t *testing.T) {
+		l, p, s := newScheduledParser()
+		session := p.Session("conn-1")
+		ops, err := session.Parse(strings.NewReader("def step { move 0.01 0 }\nfigure 0 0\nevery 2ms step"))
+		assert.Nil(t, err)
+		for _, op := range ops {
+			l.Post(op)
+		}
+		assert.Eventually(t, func() bool { return len(s.Jobs()) == 1 }, time.Second, time.Millisecond)
+		// Так з'єднання прибирає свої макроси після закриття.
+		session.DeleteMacros()
+		assert.Eventually(t, func() bool {
+			jobs := s.Jobs()
+			return len(jobs) == 1 && jobs[0].Runs >= 3
+		}, time.Second, time.Millisecond)
+		s.CancelAll()
+		l.StopAndWait()
+
+		assert.Greater(t, p.State().FigureOperations[0].Center.X, 0.02)
+		assert.Empty(t, session.Macros())
+	})
+}
+
+func TestParser_Schedule(t *testing.T) {
+	testTable := []struct {
+		name string
+		cmd  string
+		err  string
+	}{
+		{name: "missing command", cmd: "after 5s", err: "Invalid argument count"},
+		{name: "invalid duration", cmd: "every often green", err: "Invalid argument at pos 0"},
+		{name: "negative duration", cmd: "after -1s green", err: "Value at pos 0 is not positive"},
+		{name: "invalid scheduled command", cmd: "every 1s move 5 5", err: "Value at pos 0 is not in [-1,1] range"},
+		{name: "unknown scheduled command", cmd: "after 1s hello", err: "Unknown command"},
+	}
+
+	for _, test := range testTable {
+		p := &lang.Parser{Scheduler: &lang.Scheduler{}}
+		_, err := p.Parse(strings.NewReader(test.cmd))
+		assert.EqualError(t, err, test.err, test.name)
+	}
+
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("after 1s green"))
+	assert.EqualError(t, err, "Scheduling is not supported")
+}
+
+func TestJobsHandler(t *testing.T) {
+	l, p, s := newScheduledParser()
+	defer l.StopAndWait()
+	defer s.CancelAll()
+	runScript(t, l, p, "every 1h green\nafter 1h white")
+	handler := lang.JobsHandler(s)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var jobs []lang.Job
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&jobs))
+	assert.Equal(t, []lang.Job{
+		{ID: 1, Command: "green", Interval: "1h0m0s", Repeat: true},
+		{ID: 2, Command: "white", Interval: "1h0m0s"},
+	}, jobs)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/jobs/1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, len(s.Jobs()))
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/jobs/1", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/jobs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, s.Jobs())
+}
@@ -0,0 +1,148 @@
+package test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func figureCenters(p *lang.Parser) []painter.RelativePoint {
+	var res []painter.RelativePoint
+	for _, f := range p.State().FigureOperations {
+		res = append(res, f.Center)
+	}
+	return res
+}
+
+func TestParser_Variables(t *testing.T) {
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("let x = 0.5\nlet y=-$x/2\nfigure $x $y\nfigure ($x - 0.25) (-$y * 2)\nfigure y=$x*0 x=$x%0.3"))
+	assert.Nil(t, err)
+	assert.Equal(t, []painter.RelativePoint{{X: 0.5, Y: -0.25}, {X: 0.25, Y: 0.5}, {X: 0.2, Y: 0}}, figureCenters(p))
+
+	// Змінні зберігаються між запитами.
+	_, err = p.Parse(strings.NewReader("let x = $x + 0.1\nmove $x 0"))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.6, p.Vars()["x"], 1e-9)
+
+	// Після помилки змінні повертаються до попередніх значень.
+	_, err = p.Parse(strings.NewReader("let x = 1\nmove $x $z"))
+	assert.EqualError(t, err, "Undefined variable z")
+	assert.InDelta(t, 0.6, p.Vars()["x"], 1e-9)
+}
+
+func TestParser_Loops(t *testing.T) {
+	t.Run("repeat", func(t *testing.T) {
+		p := &lang.Parser{}
+		ops, err := p.Parse(strings.NewReader("figure 0 0\nrepeat 3 {\n  move 0.1 0\n  update\n}\nrepeat 0 { reset }"))
+		assert.Nil(t, err)
+		// Стан копіюється один раз перед кожним "update", а не після кожної команди.
+		assert.Equal(t, 6, len(ops))
+		for idx, x := range []float64{0.1, 0.2, 0.3} {
+			assert.InDelta(t, x, ops[idx*2].(painter.StatefulOperationList).FigureOperations[0].Center.X, 1e-9)
+			assert.Equal(t, painter.UpdateOp, ops[idx*2+1])
+		}
+		assert.InDelta(t, 0.3, figureCenters(p)[0].X, 1e-9)
+	})
+
+	t.Run("for", func(t *testing.T) {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader("let n = 2\nfor i in 0..$n { figure ($i / 10) 0; for j in 1..0 { figure ($i / 2) $j } }"))
+		assert.Nil(t, err)
+		assert.Equal(t, []painter.RelativePoint{
+			{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 0},
+			{X: 0.1, Y: 0}, {X: 0.5, Y: 1}, {X: 0.5, Y: 0},
+			{X: 0.2, Y: 0}, {X: 1, Y: 1}, {X: 1, Y: 0},
+		}, figureCenters(p))
+		_, defined := p.Vars()["i"]
+		assert.False(t, defined)
+	})
+
+	t.Run("Step limit applies to each script separately", func(t *testing.T) {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader("let x = 0"))
+		assert.Nil(t, err)
+		for i := 0; i < 3; i++ {
+			_, err := p.Parse(strings.NewReader("repeat 100 { repeat 300 { let x = $x + 1 } }"))
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, 90000.0, p.Vars()["x"])
+	})
+
+	t.Run("State is copied once per update", func(t *testing.T) {
+		p := &lang.Parser{}
+		ops, err := p.Parse(strings.NewReader("repeat 256 { figure 0.5 0.5 }\nrepeat 200 { repeat 200 { move 0 0 } }"))
+		assert.Nil(t, err)
+		if assert.Equal(t, 1, len(ops)) {
+			assert.Equal(t, painter.MaxFigures, len(ops[0].(painter.StatefulOperationList).FigureOperations))
+		}
+
+		// Кількість фігур обмежена, як і кількість прямокутників.
+		_, err = p.Parse(strings.NewReader("reset\nrepeat 10000 { figure 0.5 0.5 }"))
+		var parseErr *lang.ParseError
+		if assert.True(t, errors.As(err, &parseErr)) {
+			assert.Equal(t, "Picture already has 256 figures", parseErr.Error())
+			assert.Equal(t, 2, parseErr.Line)
+			assert.Equal(t, 16, parseErr.Column)
+		}
+		_, err = p.Edit(painter.OperationFigure{}, true)
+		assert.EqualError(t, err, "Picture already has 256 figures")
+		assert.Equal(t, painter.MaxFigures, len(p.State().FigureOperations))
+	})
+
+	t.Run("Loop variables are captured by scheduled commands", func(t *testing.T) {
+		p := &lang.Parser{Scheduler: &lang.Scheduler{}}
+		ops, err := p.Parse(strings.NewReader("for i in 1..2 { after 1s figure ($i / 10) -$i/10 }"))
+		assert.Nil(t, err)
+		if assert.Equal(t, 2, len(ops)) {
+			assert.Equal(t, "figure (2 / 10) -2/10", ops[1].(lang.ScheduleOp).Command)
+		}
+	})
+
+	testTable := []struct {
+		name      string
+		script    string
+		err       string
+		line, col int
+	}{
+		{name: "unclosed block", script: "repeat 2 {\n  update", err: "Unclosed block", line: 1, col: 10},
+		{name: "unexpected brace", script: "update\n}", err: "Unexpected }", line: 2, col: 1},
+		{name: "missing brace", script: "repeat 2\nupdate", err: "Missing { after repeat", line: 1, col: 9},
+		{name: "negative repeat", script: "repeat -1 { update }", err: "Repeat count is not in [0,10000] range", line: 1, col: 8},
+		{name: "fractional repeat", script: "repeat 1.5 { update }", err: "Value 1.5 is not an integer", line: 1, col: 8},
+		{name: "bad for", script: "for i 0..2 { update }", err: "Expected for name in from..to", line: 1, col: 1},
+		{name: "bad range", script: "for i in 2 { update }", err: "Expected range from..to", line: 1, col: 10},
+		{name: "long range", script: "for i in 0..10000 { update }", err: "Range is longer than 10000", line: 1, col: 10},
+		{name: "nested loops", script: "repeat 1000 {\n  repeat 1000 { let x = 1 }\n}", err: "Script takes more than 100000 steps", line: 2, col: 3},
+		{name: "loops in macro", script: "def spin { repeat 10000 { update } }\nrepeat 100 { spin }", err: "In spin: Script takes more than 100000 steps", line: 2, col: 14},
+		{name: "error inside loop", script: "for i in 0..5 {\n  move ($i / 2) 0\n}", err: "Value at pos 0 is not in [-1,1] range", line: 2, col: 8},
+		{name: "bad let", script: "let x 1", err: "Expected let name = value", line: 1, col: 1},
+		{name: "invalid let value", script: "let x = 1 +", err: "Invalid value", line: 1, col: 9},
+		{name: "invalid expression", script: "figure (0.1 * ) 0", err: "Invalid argument at pos 0", line: 1, col: 8},
+		{name: "division by zero", script: "figure 0 1/0", err: "Division by zero", line: 1, col: 10},
+	}
+	for _, test := range testTable {
+		t.Run(test.name, func(t *testing.T) {
+			p := &lang.Parser{}
+			_, err := p.Parse(strings.NewReader(test.script))
+			var parseErr *lang.ParseError
+			if assert.True(t, errors.As(err, &parseErr)) {
+				assert.Equal(t, test.err, parseErr.Error())
+				assert.Equal(t, test.line, parseErr.Line)
+				assert.Equal(t, test.col, parseErr.Column)
+			}
+		})
+	}
+}
+
+func TestRegistry_Keywords(t *testing.T) {
+	r := lang.NewRegistry()
+	err := r.Register(lang.Command{Name: "repeat", Op: func(*lang.Parser, lang.Args) (painter.Operation, error) {
+		return nil, nil
+	}})
+	assert.EqualError(t, err, "command name repeat is a keyword")
+}
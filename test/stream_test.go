@@ -0,0 +1,65 @@
+package test
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeListener(t *testing.T) {
+	listeners := map[string]func() (net.Listener, error){
+		"tcp": func() (net.Listener, error) { return net.Listen("tcp", "127.0.0.1:0") },
+		"unix": func() (net.Listener, error) {
+			return net.Listen("unix", filepath.Join(t.TempDir(), "painter.sock"))
+		},
+	}
+
+	for name, listen := range listeners {
+		t.Run(name, func(t *testing.T) {
+			var (
+				l  painter.Loop
+				tr testReceiver
+				p  lang.Parser
+			)
+			l.Receiver = &tr
+			l.Start(mockScreen{})
+
+			ln, err := listen()
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			defer ln.Close()
+			go lang.ServeListener(ln, &l, &p)
+
+			conn, err := net.Dial(ln.Addr().Network(), ln.Addr().String())
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			defer conn.Close()
+
+			fmt.Fprint(conn, "white\r\nfigure 0.5 0.5\n\nbgrect 0 0 2 1\nupdate\n")
+			responses := bufio.NewScanner(conn)
+			for _, expected := range []string{"OK", "OK", "OK", "ERR Value at pos 2 is not in [-1,1] range", "OK"} {
+				assert.True(t, responses.Scan())
+				assert.Equal(t, expected, responses.Text())
+			}
+
+			// Блоки можна надсилати по рядку; дужки у коментарях не враховуються.
+			fmt.Fprint(conn, "repeat 2 {\n  figure 0.1 0.1 # }\n}\ndef dot(x) {\n  figure $x $x }\ndot 0.3\n")
+			for _, expected := range []string{"...", "...", "OK", "...", "OK", "OK"} {
+				assert.True(t, responses.Scan())
+				assert.Equal(t, expected, responses.Text())
+			}
+
+			l.StopAndWait()
+			assert.Equal(t, 4, len(p.State().FigureOperations))
+			assert.Empty(t, p.State().BgRectOperations)
+		})
+	}
+}
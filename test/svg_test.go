@@ -0,0 +1,43 @@
+package test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportSVG(t *testing.T) {
+	t.Run("Empty state is exported with black background", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := painter.ExportSVG(&buf, painter.StatefulOperationList{})
+		assert.Nil(t, err)
+
+		out := buf.String()
+		assert.True(t, strings.HasPrefix(out, "<svg "))
+		assert.True(t, strings.HasSuffix(out, "</svg>\n"))
+		assert.Contains(t, out, `<rect x="0" y="0" width="800" height="800" fill="rgb(0,0,0)"/>`)
+		assert.Equal(t, 1, strings.Count(out, "<rect"))
+	})
+
+	t.Run("All parts of the state are exported in drawing order", func(t *testing.T) {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader("green\nbgrect 0.25 0.25 0.75 0.75\nfigure 0.5 0.5"))
+		assert.Nil(t, err)
+
+		var buf bytes.Buffer
+		err = painter.ExportSVG(&buf, p.State())
+		assert.Nil(t, err)
+
+		out := buf.String()
+		bg := strings.Index(out, `<rect x="0" y="0" width="800" height="800" fill="rgb(0,255,0)"/>`)
+		bgRect := strings.Index(out, `<rect x="200" y="200" width="400" height="400" fill="rgb(0,0,0)"/>`)
+		figure := strings.Index(out, `fill="rgb(255,255,0)"`)
+		assert.True(t, bg >= 0 && bgRect > bg && figure > bgRect, out)
+		// Фігура складається з двох прямокутників.
+		assert.Equal(t, 2, strings.Count(out, `fill="rgb(255,255,0)"`))
+	})
+}
@@ -0,0 +1,157 @@
+package test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/exp/shiny/screen"
+)
+
+// imageTexture - текстура, що малює у звичайне зображення, щоб перевіряти кольори пікселів.
+type imageTexture struct {
+	*image.RGBA
+}
+
+func newImageTexture(size image.Point) imageTexture {
+	return imageTexture{image.NewRGBA(image.Rectangle{Max: size})}
+}
+
+func (t imageTexture) Release()                                                     {}
+func (t imageTexture) Size() image.Point                                            { return t.Rect.Size() }
+func (t imageTexture) Upload(dp image.Point, src screen.Buffer, sr image.Rectangle) {}
+func (t imageTexture) Fill(dr image.Rectangle, src color.Color, op draw.Op) {
+	draw.Draw(t.RGBA, dr, image.NewUniform(src), image.Point{}, op)
+}
+
+func (t imageTexture) filled(x, y int) bool {
+	return t.RGBAAt(x, y) == color.RGBA{R: 0xff, G: 0xff, A: 0xff}
+}
+
+// transform повертає вказівник на m для поля OperationFigure.Transform.
+func transform(m painter.Matrix) *painter.Matrix {
+	return &m
+}
+
+func roundMatrix(m painter.Matrix) painter.Matrix {
+	round := func(v float64) float64 { return math.Round(v*1e6) / 1e6 }
+	return painter.Matrix{A: round(m.A), B: round(m.B), C: round(m.C), D: round(m.D)}
+}
+
+func TestParser_Transforms(t *testing.T) {
+	p := &lang.Parser{}
+	_, err := p.Parse(strings.NewReader("figure 0.5 0.5\nrotate 90\nscale 2"))
+	assert.Nil(t, err)
+	figure := p.State().FigureOperations[0]
+	assert.Equal(t, painter.RelativePoint{X: 0.5, Y: 0.5}, figure.Center)
+	assert.Equal(t, painter.Matrix{A: 0, B: -2, C: 2, D: 0}, roundMatrix(figure.Matrix()))
+
+	// Поворот навколо точки зміщує і центр фігури.
+	p = &lang.Parser{}
+	_, err = p.Parse(strings.NewReader("figure 0.25 0.5\nrotate 180 cx=0.5 cy=0.5\nscale 0.5 0 0"))
+	assert.Nil(t, err)
+	figure = p.State().FigureOperations[0]
+	assert.InDelta(t, 0.375, figure.Center.X, 1e-9)
+	assert.InDelta(t, 0.25, figure.Center.Y, 1e-9)
+	assert.Equal(t, painter.Matrix{A: -0.5, D: -0.5}, roundMatrix(figure.Matrix()))
+
+	_, err = p.Parse(strings.NewReader("rotate 90 0.5"))
+	assert.EqualError(t, err, "Invalid argument count")
+	_, err = p.Parse(strings.NewReader("scale 0"))
+	assert.EqualError(t, err, "Value at pos 0 is not positive")
+	// Масштаб, за якого визначник матриці дорівнює нулю, відхиляється, а фігура зберігає попереднє перетворення.
+	_, err = p.Parse(strings.NewReader("scale 1e-300"))
+	assert.EqualError(t, err, "Transform makes a figure degenerate")
+	assert.Equal(t, painter.Matrix{A: -0.5, D: -0.5}, roundMatrix(p.State().FigureOperations[0].Matrix()))
+
+	// Масштаб обмежений, щоб координати вершин не виходили за межі int.
+	_, err = p.Parse(strings.NewReader("scale 1e150"))
+	assert.EqualError(t, err, "Value at pos 0 is not in [0,100] range")
+	_, err = p.Parse(strings.NewReader("scale 100; scale 4"))
+	assert.EqualError(t, err, "Transform makes a figure more than 100 times larger")
+	assert.Equal(t, painter.Matrix{A: -0.5, D: -0.5}, roundMatrix(p.State().FigureOperations[0].Matrix()))
+}
+
+func TestMatrix_Degenerate(t *testing.T) {
+	assert.False(t, painter.Identity.Degenerate())
+	assert.False(t, painter.Rotation(1).Degenerate())
+	assert.True(t, painter.Matrix{}.Degenerate())
+	assert.True(t, painter.Matrix{A: 1, B: 2, C: 2, D: 4}.Degenerate())
+	assert.True(t, painter.Scaling(math.Inf(1)).Degenerate())
+	assert.True(t, painter.Scaling(painter.MaxScale).Drawable())
+	assert.False(t, painter.Scaling(-painter.MaxScale-1).Drawable())
+	assert.False(t, painter.Matrix{}.Drawable())
+
+	// Нульова матриця - вироджене перетворення: така фігура не малюється і не приймає кліків.
+	tx := newImageTexture(image.Pt(800, 800))
+	figure := painter.OperationFigure{Center: painter.RelativePoint{X: 0.5, Y: 0.5}, Transform: &painter.Matrix{}}
+	figure.Do(tx)
+	assert.False(t, tx.filled(400, 400))
+	var sol painter.StatefulOperationList
+	figure.SetState(&sol)
+	assert.Equal(t, -1, sol.FigureAt(painter.RelativePoint{X: 0.5, Y: 0.5}, image.Pt(800, 800)))
+
+	// Перетворення, що зробило б фігуру виродженою, її не змінює.
+	sol.FigureOperations[0].Transform = nil
+	sol.Update(painter.TransformTweaker{Matrix: painter.Scaling(0)})
+	assert.Nil(t, sol.FigureOperations[0].Transform)
+}
+
+func TestOperationFigure_Transform(t *testing.T) {
+	t.Run("Rotated figure is rasterized", func(t *testing.T) {
+		tx := newImageTexture(image.Pt(800, 800))
+		painter.OperationFigure{Center: painter.RelativePoint{X: 0.5, Y: 0.5}, Transform: transform(painter.Rotation(math.Pi / 2))}.Do(tx)
+		// Перекладина над центром після повороту опиняється праворуч від нього.
+		assert.True(t, tx.filled(480, 320))
+		assert.True(t, tx.filled(480, 480))
+		assert.False(t, tx.filled(320, 320))
+		assert.False(t, tx.filled(320, 480))
+
+		// Фігура, повернута на 45 градусів, не є прямокутником.
+		tx = newImageTexture(image.Pt(800, 800))
+		painter.OperationFigure{Center: painter.RelativePoint{X: 0.5, Y: 0.5}, Transform: transform(painter.Rotation(math.Pi / 4))}.Do(tx)
+		assert.True(t, tx.filled(400, 400))
+		assert.True(t, tx.filled(520, 407))
+		assert.False(t, tx.filled(280, 407))
+		assert.False(t, tx.filled(330, 330))
+	})
+
+	t.Run("Rotated figure is clipped to the texture", func(t *testing.T) {
+		tx := newImageTexture(image.Pt(100, 100))
+		painter.OperationFigure{Center: painter.RelativePoint{X: 1, Y: 1}, Transform: transform(painter.Rotation(0.3))}.Do(tx)
+		assert.True(t, tx.filled(99, 99))
+	})
+
+	t.Run("SVG export", func(t *testing.T) {
+		p := &lang.Parser{}
+		_, err := p.Parse(strings.NewReader("figure 0.5 0.5\nscale 0.5"))
+		assert.Nil(t, err)
+		var buf bytes.Buffer
+		assert.Nil(t, painter.ExportSVG(&buf, p.State()))
+		assert.Contains(t, buf.String(), `<rect x="383" y="378" width="35" height="80" fill="rgb(255,255,0)"/>`)
+
+		_, err = p.Parse(strings.NewReader("rotate 30"))
+		assert.Nil(t, err)
+		buf.Reset()
+		assert.Nil(t, painter.ExportSVG(&buf, p.State()))
+		assert.Equal(t, 2, strings.Count(buf.String(), "<polygon points="))
+	})
+}
+
+func TestScaleAnimation(t *testing.T) {
+	anim := painter.ScaleAnimation(4, nil, time.Second, nil)
+	var sol painter.StatefulOperationList
+	painter.OperationFigure{}.SetState(&sol)
+	for _, step := range [][2]float64{{0, 0.3}, {0.3, 0.5}, {0.5, 1}} {
+		sol.Update(anim.Step(step[0], step[1]))
+	}
+	assert.Equal(t, painter.Scaling(4), roundMatrix(sol.FigureOperations[0].Matrix()))
+}
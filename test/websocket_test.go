@@ -0,0 +1,66 @@
+package test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"github.com/MytsV/architecture-lab-3/painter/lang"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/websocket"
+)
+
+func TestWebSocketHandler(t *testing.T) {
+	var (
+		l  painter.Loop
+		tr testReceiver
+		p  lang.Parser
+	)
+	l.Receiver = &tr
+	l.Start(mockScreen{})
+
+	server := httptest.NewServer(lang.WebSocketHandler(&l, &p))
+	defer server.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), "", server.URL)
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	defer ws.Close()
+
+	receive := func() string {
+		var msg string
+		if err := websocket.Message.Receive(ws, &msg); err != nil {
+			t.Fatalf("unexpected err: %v", err)
+		}
+		return msg
+	}
+
+	// Кожен рядок повідомлення отримує власну відповідь.
+	assert.Nil(t, websocket.Message.Send(ws, "green\nfigure 0.5 0.5\nupdate\n"))
+	assert.Equal(t, "OK", receive())
+	assert.Equal(t, "OK", receive())
+	assert.Equal(t, "OK", receive())
+
+	assert.Nil(t, websocket.Message.Send(ws, "move 3 3"))
+	assert.Equal(t, "ERR Value at pos 0 is not in [-1,1] range", receive())
+
+	assert.Nil(t, websocket.Message.Send(ws, "hello\n\nmove 0.1 0.1"))
+	assert.Equal(t, "ERR Unknown command", receive())
+	assert.Equal(t, "OK", receive())
+	assert.Equal(t, "OK", receive())
+
+	// Блок може бути розбитий між повідомленнями і виконується, коли надходить закриваюча дужка.
+	assert.Nil(t, websocket.Message.Send(ws, "repeat 2 {\n  move 0.05 0"))
+	assert.Equal(t, "...", receive())
+	assert.Equal(t, "...", receive())
+	assert.Nil(t, websocket.Message.Send(ws, "}"))
+	assert.Equal(t, "OK", receive())
+
+	l.StopAndWait()
+	figures := p.State().FigureOperations
+	assert.Equal(t, 1, len(figures))
+	assert.InDelta(t, 0.7, figures[0].Center.X, 0.00001)
+	assert.IsType(t, &mockTexture{}, tr.LastTexture)
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"strings"
+
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+	"golang.org/x/mobile/event/size"
+)
+
+// Типи подій, які Visualizer передає у OnEvent.
+const (
+	EventClick  = "click"
+	EventKey    = "key"
+	EventResize = "resize"
+	EventClose  = "close"
+)
+
+// Event описує дію користувача у вікні у вигляді, зручному для передачі зовнішнім програмам у JSON.
+type Event struct {
+	Type string `json:"type"`
+	// At - положення курсора на текстурі для кліків та натискань клавіш (див. Point).
+	At *Point `json:"at,omitempty"`
+	// Button - кнопка миші: left, middle або right.
+	Button string `json:"button,omitempty"`
+	// Key - назва клавіші, наприклад A, Space або LeftArrow; Rune - введений символ, якщо він є.
+	Key       string   `json:"key,omitempty"`
+	Rune      string   `json:"rune,omitempty"`
+	Modifiers []string `json:"modifiers,omitempty"`
+	// Width та Height - новий розмір вікна у пікселях для події resize.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+}
+
+// Point - точка у частках розміру текстури, що відповідають координатам команд. Режим масштабування вікна
+// враховується (див. ScaleMode.ToTexture), тому на полях навколо текстури координати виходять за межі [0,1].
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+var buttonNames = map[mouse.Button]string{
+	mouse.ButtonLeft:   "left",
+	mouse.ButtonMiddle: "middle",
+	mouse.ButtonRight:  "right",
+}
+
+var modifierNames = []struct {
+	mod  key.Modifiers
+	name string
+}{
+	{key.ModShift, "shift"},
+	{key.ModControl, "control"},
+	{key.ModAlt, "alt"},
+	{key.ModMeta, "meta"},
+}
+
+// emit передає подію у OnEvent, якщо обробник встановлений.
+func (pw *Visualizer) emit(e Event) {
+	if pw.OnEvent != nil {
+		pw.OnEvent(e)
+	}
+}
+
+func (pw *Visualizer) clickEvent(e mouse.Event) Event {
+	at := pw.relative(e.X, e.Y)
+	return Event{Type: EventClick, At: &Point{X: at.X, Y: at.Y}, Button: buttonNames[e.Button]}
+}
+
+func (pw *Visualizer) keyEvent(e key.Event) Event {
+	at := pw.relative(pw.cursor.X, pw.cursor.Y)
+	ev := Event{Type: EventKey, At: &Point{X: at.X, Y: at.Y}, Key: strings.TrimPrefix(e.Code.String(), "Code")}
+	if e.Rune > 0 {
+		ev.Rune = string(e.Rune)
+	}
+	for _, m := range modifierNames {
+		if e.Modifiers&m.mod != 0 {
+			ev.Modifiers = append(ev.Modifiers, m.name)
+		}
+	}
+	return ev
+}
+
+func resizeEvent(e size.Event) Event {
+	return Event{Type: EventResize, Width: e.WidthPx, Height: e.HeightPx}
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"log"
+	"time"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// DebugInfo містить дані, які показує оверлей налагодження.
+type DebugInfo struct {
+	// FPS - кількість кадрів, показаних за останню секунду; Dropped - кількість текстур, замінених новішими до того,
+	// як їх встигли показати.
+	FPS     int
+	Dropped int
+	// Mouse - положення курсора у координатах текстури.
+	Mouse painter.RelativePoint
+	// QueueLen - кількість операцій у черзі циклу подій; LastCommand - остання отримана команда. Заповнюються
+	// функцією Visualizer.DebugSource.
+	QueueLen    int
+	LastCommand string
+}
+
+// maxCommandLength обмежує довжину команди в оверлеї, щоб рядок вмістився у його ширину.
+const maxCommandLength = 36
+
+// Lines повертає рядки тексту оверлея.
+func (d DebugInfo) Lines() []string {
+	command := []rune(d.LastCommand)
+	if len(command) > maxCommandLength {
+		command = append(command[:maxCommandLength-3], []rune("...")...)
+	}
+	return []string{
+		fmt.Sprintf("FPS: %d", d.FPS),
+		fmt.Sprintf("Queue: %d", d.QueueLen),
+		fmt.Sprintf("Dropped: %d", d.Dropped),
+		fmt.Sprintf("Last: %s", string(command)),
+		fmt.Sprintf("Mouse: %.3f, %.3f", d.Mouse.X, d.Mouse.Y),
+	}
+}
+
+const (
+	overlayPadding    = 8
+	overlayLineHeight = 15
+	overlayRefresh    = 500 * time.Millisecond
+)
+
+var overlaySize = image.Pt(2*overlayPadding+(maxCommandLength+6)*7, 2*overlayPadding+5*overlayLineHeight)
+
+// stats накопичує дані про показані кадри для оверлея.
+type stats struct {
+	frames  []time.Time // час показу кадрів за останню секунду
+	dropped int
+	pending bool // остання отримана текстура ще не показана
+}
+
+// received враховує нову текстуру: якщо попередня так і не була показана, вона вважається пропущеною.
+func (s *stats) received() {
+	if s.pending {
+		s.dropped++
+	}
+	s.pending = true
+}
+
+// shown враховує показ отриманої текстури.
+func (s *stats) shown(now time.Time) {
+	if !s.pending {
+		return
+	}
+	s.pending = false
+	s.frames = append(s.frames, now)
+}
+
+func (s *stats) fps(now time.Time) int {
+	idx := 0
+	for idx < len(s.frames) && now.Sub(s.frames[idx]) > time.Second {
+		idx++
+	}
+	s.frames = s.frames[idx:]
+	return len(s.frames)
+}
+
+func (pw *Visualizer) debugInfo() DebugInfo {
+	info := DebugInfo{
+		FPS:     pw.stats.fps(time.Now()),
+		Dropped: pw.stats.dropped,
+		Mouse:   pw.relative(pw.cursor.X, pw.cursor.Y),
+	}
+	if pw.DebugSource != nil {
+		pw.DebugSource(&info)
+	}
+	return info
+}
+
+// drawOverlay малює оверлей налагодження у лівому верхньому куті вікна.
+func (pw *Visualizer) drawOverlay() {
+	if pw.overlay == nil {
+		b, err := pw.s.NewBuffer(overlaySize)
+		if err != nil {
+			log.Printf("ERROR: %s", err)
+			pw.showOverlay = false
+			return
+		}
+		pw.overlay = b
+	}
+
+	img := pw.overlay.RGBA()
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 0x20, G: 0x20, B: 0x20, A: 0xff}), image.Point{}, draw.Src)
+	d := font.Drawer{Dst: img, Src: image.White, Face: basicfont.Face7x13}
+	for idx, line := range pw.debugInfo().Lines() {
+		d.Dot = fixed.P(overlayPadding, overlayPadding+(idx+1)*overlayLineHeight-3)
+		d.DrawString(line)
+	}
+	pw.w.Upload(image.Pt(overlayPadding, overlayPadding), pw.overlay, pw.overlay.Bounds())
+}
+
+// releaseOverlay звільняє буфер оверлея.
+func (pw *Visualizer) releaseOverlay() {
+	if pw.overlay != nil {
+		pw.overlay.Release()
+		pw.overlay = nil
+	}
+}
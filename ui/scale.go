@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/MytsV/architecture-lab-3/painter"
+)
+
+// ScaleMode задає, як текстура вписується у вікно, розміри якого відрізняються від розмірів текстури.
+type ScaleMode string
+
+const (
+	// ScaleStretch розтягує текстуру на все вікно без збереження пропорцій. Використовується за замовчуванням.
+	ScaleStretch ScaleMode = "stretch"
+	// ScaleFit вписує текстуру у вікно зі збереженням пропорцій, залишаючи чорні поля по краях.
+	ScaleFit ScaleMode = "fit"
+	// ScaleFill заповнює все вікно зі збереженням пропорцій, обрізаючи частину текстури, що не вміщується.
+	ScaleFill ScaleMode = "fill"
+	// ScaleInteger збільшує текстуру у ціле число разів, щоб пікселі залишалися чіткими. Якщо текстура не вміщується
+	// у вікно, вона зменшується як у ScaleFit.
+	ScaleInteger ScaleMode = "integer"
+)
+
+// ScaleModes містить усі підтримувані режими.
+var ScaleModes = []ScaleMode{ScaleStretch, ScaleFit, ScaleFill, ScaleInteger}
+
+// ParseScaleMode перевіряє назву режиму масштабування.
+func ParseScaleMode(name string) (ScaleMode, error) {
+	for _, m := range ScaleModes {
+		if string(m) == name {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("unknown scale mode %q, expected one of %v", name, ScaleModes)
+}
+
+// Viewport повертає прямокутник у вікні розміру window, у який малюється текстура розміру texture. У режимі
+// ScaleFill прямокутник виходить за межі вікна. Прямокутник центрується у вікні.
+func (m ScaleMode) Viewport(window, texture image.Point) image.Rectangle {
+	if texture.X <= 0 || texture.Y <= 0 || m == ScaleStretch || m == "" {
+		return image.Rectangle{Max: window}
+	}
+
+	sx, sy := float64(window.X)/float64(texture.X), float64(window.Y)/float64(texture.Y)
+	var k float64
+	switch m {
+	case ScaleFill:
+		k = math.Max(sx, sy)
+	case ScaleInteger:
+		if k = math.Floor(math.Min(sx, sy)); k < 1 {
+			k = math.Min(sx, sy)
+		}
+	default:
+		k = math.Min(sx, sy)
+	}
+
+	size := image.Pt(int(math.Round(float64(texture.X)*k)), int(math.Round(float64(texture.Y)*k)))
+	min := window.Sub(size).Div(2)
+	return image.Rectangle{Min: min, Max: min.Add(size)}
+}
+
+// ToTexture перетворює точку (x, y) у вікні розміру window в частки розміру текстури texture, яка вписана у вікно
+// за режимом m (див. Viewport). Точки на полях навколо текстури отримують координати за межами [0,1].
+func (m ScaleMode) ToTexture(window, texture image.Point, x, y float64) painter.RelativePoint {
+	viewport := m.Viewport(window, texture)
+	return painter.RelativePoint{
+		X: (x - float64(viewport.Min.X)) / float64(viewport.Dx()),
+		Y: (y - float64(viewport.Min.Y)) / float64(viewport.Dy()),
+	}
+}
+
+// project обрізає прямокутник viewport межами вікна і повертає видиму частину вікна та відповідну їй частину
+// текстури.
+func project(viewport image.Rectangle, window, texture image.Point) (dr, sr image.Rectangle) {
+	dr = viewport.Intersect(image.Rectangle{Max: window})
+	if dr.Empty() {
+		return dr, image.Rectangle{}
+	}
+	toTexture := func(p image.Point) image.Point {
+		return image.Pt(
+			int(math.Round(float64(p.X-viewport.Min.X)*float64(texture.X)/float64(viewport.Dx()))),
+			int(math.Round(float64(p.Y-viewport.Min.Y)*float64(texture.Y)/float64(viewport.Dy()))),
+		)
+	}
+	return dr, image.Rectangle{Min: toTexture(dr.Min), Max: toTexture(dr.Max)}
+}
@@ -4,7 +4,10 @@ import (
 	"image"
 	"image/color"
 	"log"
+	"sync"
+	"time"
 
+	"github.com/MytsV/architecture-lab-3/painter"
 	"golang.org/x/exp/shiny/driver"
 	"golang.org/x/exp/shiny/imageutil"
 	"golang.org/x/exp/shiny/screen"
@@ -18,29 +21,95 @@ import (
 
 const windowSize = 800
 
+// Editor змінює малюнок у відповідь на дії користувача у вікні. Точки задаються у частках розміру текстури.
+type Editor interface {
+	PlaceFigure(at painter.RelativePoint) error
+	// FigureAt повертає номер фігури під точкою at або -1.
+	FigureAt(at painter.RelativePoint) int
+	// MoveFigure зміщує фігуру; record встановлюється для першого зміщення перетягування, щоб воно скасовувалося
+	// цілком.
+	MoveFigure(idx int, offset painter.RelativePoint, record bool) error
+	Reset() error
+	Undo() error
+}
+
 type Visualizer struct {
-	Title         string
+	Title string
+	// Debug вмикає журнал усіх подій вікна. Незалежно від нього клавіша F3 показує та ховає оверлей налагодження.
 	Debug         bool
 	OnScreenReady func(s screen.Screen)
+	// Editor отримує дії користувача, коли у вікні показується текстура з циклу подій: клік додає фігуру, перетягування
+	// зміщує фігуру під курсором, R очищує малюнок, U або Ctrl+Z скасовує останню зміну. Якщо не вказаний, вікно
+	// лише показує текстуру.
+	Editor Editor
+	// OnEvent викликається у циклі подій вікна для кожного кліку, натискання клавіші, зміни розміру та при закритті
+	// вікна. Обробник не повинен блокуватися.
+	OnEvent func(e Event)
+	// Scale задає, як текстура вписується у вікно при зміні його розміру. Координати миші перетворюються у
+	// координати текстури відповідно до режиму.
+	Scale ScaleMode
+	// DebugSource доповнює дані оверлея налагодження станом циклу подій та парсера.
+	DebugSource func(info *DebugInfo)
 
+	s    screen.Screen
 	w    screen.Window
 	tx   chan screen.Texture
 	done chan struct{}
 
-	sz size.Event
-	mp image.Point
+	sz   size.Event
+	tsz  image.Point // розмір останньої показаної текстури
+	mp   image.Point
+	drag *drag // фігура, яку зараз перетягують
+
+	cursor mouse.Event // остання подія миші, з якої береться положення курсора
+
+	stats       stats
+	showOverlay bool
+	overlay     screen.Buffer // буфер, у якому малюється текст оверлея
+}
+
+type drag struct {
+	index int
+	last  painter.RelativePoint
+	moved bool
 }
 
 func (pw *Visualizer) Main() {
+	Main(pw)
+}
+
+// Main відкриває вікна windows на одному екрані і повертає керування, коли всі вони закриті. Текстури належать
+// екрану, на якому створені, тому вікна, що показують текстури одного циклу подій, потрібно відкривати разом.
+func Main(windows ...*Visualizer) {
+	for _, pw := range windows {
+		pw.init()
+	}
+	driver.Main(func(s screen.Screen) {
+		var wg sync.WaitGroup
+		for _, pw := range windows {
+			wg.Add(1)
+			go func(pw *Visualizer) {
+				defer wg.Done()
+				pw.run(s)
+			}(pw)
+		}
+		wg.Wait()
+	})
+}
+
+func (pw *Visualizer) init() {
 	pw.tx = make(chan screen.Texture, 1024)
 	pw.done = make(chan struct{})
 	pw.mp.X = windowSize / 2
 	pw.mp.Y = windowSize / 2
-	driver.Main(pw.run)
 }
 
 func (pw *Visualizer) Update(t screen.Texture) {
-	pw.tx <- t
+	// Після закриття вікна текстури відкидаються, щоб цикл подій не блокувався, поки він оновлює інші вікна.
+	select {
+	case pw.tx <- t:
+	case <-pw.done:
+	}
 }
 
 func (pw *Visualizer) run(s screen.Screen) {
@@ -59,11 +128,12 @@ func (pw *Visualizer) run(s screen.Screen) {
 		log.Fatal("Failed to initialize the app window:", err)
 	}
 	defer func() {
+		pw.releaseOverlay()
 		w.Release()
 		close(pw.done)
 	}()
 
-	pw.w = w
+	pw.s, pw.w = s, w
 
 	events := make(chan any)
 	go func() {
@@ -82,16 +152,28 @@ func (pw *Visualizer) run(s screen.Screen) {
 
 	var t screen.Texture
 
+	// Оверлей оновлюється періодично, щоб показувати актуальні дані, навіть коли нові кадри не надходять.
+	refresh := time.NewTicker(overlayRefresh)
+	defer refresh.Stop()
+
 	for {
 		select {
 		case e, ok := <-events:
 			if !ok {
+				pw.emit(Event{Type: EventClose})
 				return
 			}
 			pw.handleEvent(e, t)
 
 		case t = <-pw.tx:
+			pw.tsz = t.Size()
+			pw.stats.received()
 			w.Send(paint.Event{})
+
+		case <-refresh.C:
+			if pw.showOverlay {
+				w.Send(paint.Event{})
+			}
 		}
 	}
 }
@@ -115,17 +197,36 @@ func (pw *Visualizer) handleEvent(e any, t screen.Texture) {
 
 	case size.Event: // Оновлення даних про розмір вікна.
 		pw.sz = e
+		pw.emit(resizeEvent(e))
 
 	case error:
 		log.Printf("ERROR: %s", e)
 
 	case mouse.Event:
+		pw.cursor = e
+		if e.Direction == mouse.DirPress && !e.Button.IsWheel() {
+			pw.emit(pw.clickEvent(e))
+		}
 		if t == nil {
 			if e.Button == mouse.ButtonLeft && e.Direction == mouse.DirPress {
 				pw.mp.X = int(e.X)
 				pw.mp.Y = int(e.Y)
 				pw.w.Send(paint.Event{})
 			}
+		} else if pw.Editor != nil {
+			pw.edit(e)
+		}
+
+	case key.Event:
+		if e.Direction == key.DirPress {
+			pw.emit(pw.keyEvent(e))
+			if e.Code == key.CodeF3 && e.Modifiers == 0 {
+				pw.showOverlay = !pw.showOverlay
+				pw.w.Send(paint.Event{})
+			}
+		}
+		if t != nil && pw.Editor != nil && e.Direction == key.DirPress {
+			pw.shortcut(e)
 		}
 
 	case paint.Event:
@@ -134,12 +235,29 @@ func (pw *Visualizer) handleEvent(e any, t screen.Texture) {
 			pw.drawDefaultUI()
 		} else {
 			// Використання текстури отриманої через виклик Update.
-			pw.w.Scale(pw.sz.Bounds(), t, t.Bounds(), draw.Src, nil)
+			pw.drawTexture(t)
+			pw.stats.shown(time.Now())
+		}
+		if pw.showOverlay {
+			pw.drawOverlay()
 		}
 		pw.w.Publish()
 	}
 }
 
+// drawTexture масштабує текстуру у вікно відповідно до режиму Scale і зафарбовує поля навколо неї.
+func (pw *Visualizer) drawTexture(t screen.Texture) {
+	window := pw.window()
+	viewport := pw.Scale.Viewport(window, t.Size())
+	dr, sr := project(viewport, window, t.Size())
+	if dr != (image.Rectangle{Max: window}) {
+		pw.w.Fill(image.Rectangle{Max: window}, color.Black, draw.Src)
+	}
+	if !dr.Empty() && !sr.Empty() {
+		pw.w.Scale(dr, t, sr, draw.Src, nil)
+	}
+}
+
 func (pw *Visualizer) drawDefaultUI() {
 	pw.w.Fill(pw.sz.Bounds(), color.Black, draw.Src) // Фон.
 
@@ -153,13 +271,64 @@ func (pw *Visualizer) drawDefaultUI() {
 }
 
 func (pw *Visualizer) DrawFigure(x, y int) {
-	//Виміри фігури
-	hlen := 115
-	hwidth := 35
-	yellow := color.RGBA{R: 0xff, G: 0xff, A: 0xff}
-
-	horizontal := image.Rect(x-hlen, y-hlen, x+hlen, y-hlen+hwidth*2)
-	pw.w.Fill(horizontal, yellow, draw.Src)
-	vertical := image.Rect(x-hwidth, y-hlen, x+hwidth, y+hlen)
-	pw.w.Fill(vertical, yellow, draw.Src)
+	painter.DrawFigure(pw.w, pw.sz.Bounds(), image.Pt(x, y), painter.Identity, painter.FigureColor, painter.BlendSrc)
+}
+
+// window повертає розмір вікна у пікселях. До першої події size.Event використовується початковий розмір.
+func (pw *Visualizer) window() image.Point {
+	if pw.sz.WidthPx == 0 || pw.sz.HeightPx == 0 {
+		return image.Pt(windowSize, windowSize)
+	}
+	return image.Pt(pw.sz.WidthPx, pw.sz.HeightPx)
+}
+
+// relative перетворює координати у вікні в частки розміру текстури з урахуванням режиму Scale (див.
+// ScaleMode.ToTexture).
+func (pw *Visualizer) relative(x, y float32) painter.RelativePoint {
+	texture := pw.tsz
+	if texture.X == 0 || texture.Y == 0 {
+		texture = image.Pt(windowSize, windowSize)
+	}
+	return pw.Scale.ToTexture(pw.window(), texture, float64(x), float64(y))
+}
+
+// edit перетворює події миші на зміни малюнку: клік на порожньому місці додає фігуру, а натискання на фігурі
+// починає її перетягування.
+func (pw *Visualizer) edit(e mouse.Event) {
+	at := pw.relative(e.X, e.Y)
+	var err error
+	switch {
+	case e.Button == mouse.ButtonLeft && e.Direction == mouse.DirPress:
+		if at.X < 0 || at.X > 1 || at.Y < 0 || at.Y > 1 {
+			return // клік на полях навколо текстури
+		}
+		if idx := pw.Editor.FigureAt(at); idx >= 0 {
+			pw.drag = &drag{index: idx, last: at}
+		} else {
+			err = pw.Editor.PlaceFigure(at)
+		}
+	case e.Direction == mouse.DirNone && pw.drag != nil:
+		offset := painter.RelativePoint{X: at.X - pw.drag.last.X, Y: at.Y - pw.drag.last.Y}
+		err = pw.Editor.MoveFigure(pw.drag.index, offset, !pw.drag.moved)
+		pw.drag.last, pw.drag.moved = at, true
+	case e.Button == mouse.ButtonLeft && e.Direction == mouse.DirRelease:
+		pw.drag = nil
+	}
+	if err != nil {
+		log.Printf("ERROR: %s", err)
+	}
+}
+
+// shortcut виконує дію, призначену клавіші.
+func (pw *Visualizer) shortcut(e key.Event) {
+	var err error
+	switch {
+	case e.Code == key.CodeR && e.Modifiers == 0:
+		err = pw.Editor.Reset()
+	case e.Code == key.CodeU && e.Modifiers == 0, e.Code == key.CodeZ && e.Modifiers&key.ModControl != 0:
+		err = pw.Editor.Undo()
+	}
+	if err != nil {
+		log.Printf("ERROR: %s", err)
+	}
 }